@@ -1,7 +1,9 @@
 package pgsql
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +15,9 @@ type Args struct {
 	sqlNamedArgs map[string]int
 	args         []interface{}
 	onlyNamed    bool
+	errs         []error
+
+	inArrayThreshold int
 }
 
 func init() {
@@ -42,23 +47,141 @@ func (args *Args) Add(arg interface{}) string {
 func (args *Args) add(arg interface{}) int {
 	idx := len(args.args)
 
+	if a, ok := arg.(namedArgs); ok {
+		if args.namedArgs == nil {
+			args.namedArgs = map[string]int{}
+		}
+
+		// A name is bound to its first value. Adding it again with an equal value refers to the same arg.
+		if p, ok := args.namedArgs[a.name]; ok {
+			if !reflect.DeepEqual(args.args[p], a.arg) {
+				args.errs = append(args.errs, fmt.Errorf("%w: %v", ErrDuplicateName, a.name))
+			}
+
+			return p
+		}
+
+		idx = args.add(a.arg)
+		args.namedArgs[a.name] = idx
+		return idx
+	}
+
 	args.args = append(args.args, arg)
 	return idx
 }
 
+// SetInArrayThreshold sets the number of values above which `Cond#In` and `Cond#NotIn`
+// send all values as one array parameter like "field = ANY($1)" instead of "field IN ($1, $2, ...)".
+// It applies only if all values are of the same type, so that they can be converted to a typed slice,
+// and only to conditions created after it's set.
+//
+// It's 0 by default, which never uses an array parameter.
+// Enable it only if the driver supports slices as parameters, e.g. pgx.
+func (args *Args) SetInArrayThreshold(threshold int) {
+	args.inArrayThreshold = threshold
+}
+
+// Clone returns a deep copy of args.
+// Nested builders created in this package are cloned as well,
+// so that the copy can be modified without affecting args.
+func (args *Args) Clone() *Args {
+	clone := &Args{
+		onlyNamed:        args.onlyNamed,
+		inArrayThreshold: args.inArrayThreshold,
+	}
+
+	if args.namedArgs != nil {
+		clone.namedArgs = make(map[string]int, len(args.namedArgs))
+
+		for k, v := range args.namedArgs {
+			clone.namedArgs[k] = v
+		}
+	}
+
+	if args.errs != nil {
+		clone.errs = make([]error, len(args.errs))
+		copy(clone.errs, args.errs)
+	}
+
+	if args.sqlNamedArgs != nil {
+		clone.sqlNamedArgs = make(map[string]int, len(args.sqlNamedArgs))
+
+		for k, v := range args.sqlNamedArgs {
+			clone.sqlNamedArgs[k] = v
+		}
+	}
+
+	if args.args != nil {
+		clone.args = make([]interface{}, 0, len(args.args))
+
+		for _, arg := range args.args {
+			if b, ok := arg.(builderCloner); ok {
+				arg = b.cloneBuilder()
+			}
+
+			clone.args = append(clone.args, arg)
+		}
+	}
+
+	return clone
+}
+
+// builderCloner is a Builder which can be cloned by `Args#Clone`.
+type builderCloner interface {
+	cloneBuilder() Builder
+}
+
 // Compile compiles builder's format to standard sql and returns associated args.
 //
 // The format string uses a special syntax to represent arguments.
 //
 //	$? refers successive arguments passed in the call. It works similar as `%v` in `fmt.Sprintf`.
 //	$0 $1 ... $n refers nth-argument passed in the call. Next $? will use arguments n+1.
+//	             All references to the same argument share one $n placeholder.
 //	${name} refers a named argument created by `Named` with `name`.
+//	        All references to the same name share one $n placeholder.
 //	$$ is a "$" string.
 func (args *Args) Compile(format string, initialValue ...interface{}) (query string, values []interface{}) {
-	buf := &strings.Builder{}
+	query, values, _, _ = args.compile(format, initialValue)
+	return
+}
+
+// CompileE is like Compile but also reports errors.
+// References to unknown named arguments or to arguments out of range are
+// reported with ErrUnresolvedName or ErrArgOutOfRange.
+// A name added by `Named` again with a different value is reported with ErrDuplicateName.
+// Errors reported by `BuildE` of nested builders are reported as well.
+func (args *Args) CompileE(format string, initialValue ...interface{}) (query string, values []interface{}, err error) {
+	query, values, _, err = args.compile(format, initialValue)
+	return
+}
+
+// compileContext keeps the state of a compilation.
+type compileContext struct {
+	buf      strings.Builder
+	values   []interface{}
+	compiled map[int]string // Compiled args by index in args.
+	idxs     map[int]string // Names of named args by index in args.
+	names    map[int]string // Names of values referred by ${name}.
+	errs     []error
+}
+
+func (args *Args) compile(format string, initialValue []interface{}) (query string, values []interface{}, names map[int]string, err error) {
+	ctx := &compileContext{
+		values: initialValue,
+		errs:   append([]error(nil), args.errs...),
+	}
+	buf := &ctx.buf
 	idx := strings.IndexRune(format, '$')
 	offset := 0
-	values = initialValue
+
+	if len(args.namedArgs) > 0 {
+		ctx.idxs = make(map[int]string, len(args.namedArgs))
+
+		for name, p := range args.namedArgs {
+			ctx.idxs[p] = name
+		}
+	}
 
 	for idx >= 0 && len(format) > 0 {
 		if idx > 0 {
@@ -77,11 +200,15 @@ func (args *Args) Compile(format string, initialValue ...interface{}) (query str
 			buf.WriteRune('$')
 			format = format[1:]
 		} else if r == '{' {
-			format, values = args.compileNamed(buf, format, values)
+			format = args.compileNamed(ctx, format)
 		} else if !args.onlyNamed && '0' <= r && r <= '9' {
-			format, values, offset = args.compileDigits(buf, format, values, offset)
+			format, offset = args.compileDigits(ctx, format, offset)
 		} else if !args.onlyNamed && r == '?' {
-			format, values, offset = args.compileSuccessive(buf, format[1:], values, offset)
+			if offset >= len(args.args) {
+				ctx.errs = append(ctx.errs, fmt.Errorf("%w: $? refers arg %v but there are %v args", ErrArgOutOfRange, offset, len(args.args)))
+			}
+
+			format, offset = args.compileSuccessive(ctx, format[1:], offset)
 		} else {
 			// For unknown $ expression format, treat it as a normal $ rune.
 			buf.WriteRune('$')
@@ -95,6 +222,7 @@ func (args *Args) Compile(format string, initialValue ...interface{}) (query str
 	}
 
 	query = buf.String()
+	values = ctx.values
 
 	if len(args.sqlNamedArgs) > 0 {
 		// Stabilize the sequence to make it easier to write test cases.
@@ -111,10 +239,12 @@ func (args *Args) Compile(format string, initialValue ...interface{}) (query str
 		}
 	}
 
+	names = ctx.names
+	err = errors.Join(ctx.errs...)
 	return
 }
 
-func (args *Args) compileNamed(buf *strings.Builder, format string, values []interface{}) (string, []interface{}) {
+func (args *Args) compileNamed(ctx *compileContext, format string) string {
 	i := 1
 
 	for ; i < len(format) && format[i] != '}'; i++ {
@@ -123,20 +253,54 @@ func (args *Args) compileNamed(buf *strings.Builder, format string, values []int
 
 	// Invalid $ format. Ignore it.
 	if i == len(format) {
-		return format, values
+		return format
 	}
 
 	name := format[1:i]
 	format = format[i+1:]
+	p, ok := args.namedArgs[name]
 
-	if p, ok := args.namedArgs[name]; ok {
-		format, values, _ = args.compileSuccessive(buf, format, values, p)
+	if !ok {
+		ctx.errs = append(ctx.errs, fmt.Errorf("%w: ${%v}", ErrUnresolvedName, name))
+		return format
 	}
 
-	return format, values
+	args.compileIndex(ctx, p)
+	return format
 }
 
-func (args *Args) compileDigits(buf *strings.Builder, format string, values []interface{}, offset int) (string, []interface{}, int) {
+// compileIndex compiles the arg at p.
+// All references to the same arg, either by ${name}, $n or $?,
+// share the result compiled at the first time.
+func (args *Args) compileIndex(ctx *compileContext, p int) {
+	if compiled, ok := ctx.compiled[p]; ok {
+		ctx.buf.WriteString(compiled)
+		return
+	}
+
+	start := ctx.buf.Len()
+	idx := len(ctx.values)
+	args.compileArg(ctx, args.args[p])
+	compiled := ctx.buf.String()[start:]
+
+	if ctx.compiled == nil {
+		ctx.compiled = map[int]string{}
+	}
+
+	ctx.compiled[p] = compiled
+	name, ok := ctx.idxs[p]
+
+	// Remember the name if the named arg is a value rather than a builder or raw expression.
+	if ok && len(ctx.values) == idx+1 && compiled == fmt.Sprintf("$%d", idx+1) {
+		if ctx.names == nil {
+			ctx.names = map[int]string{}
+		}
+
+		ctx.names[idx] = name
+	}
+}
+
+func (args *Args) compileDigits(ctx *compileContext, format string, offset int) (string, int) {
 	i := 1
 
 	for ; i < len(format) && '0' <= format[i] && format[i] <= '9'; i++ {
@@ -147,45 +311,70 @@ func (args *Args) compileDigits(buf *strings.Builder, format string, values []in
 	format = format[i:]
 
 	if pointer, err := strconv.Atoi(digits); err == nil {
-		return args.compileSuccessive(buf, format, values, pointer)
+		if pointer >= len(args.args) {
+			ctx.errs = append(ctx.errs, fmt.Errorf("%w: $%v refers arg %v but there are %v args", ErrArgOutOfRange, digits, pointer, len(args.args)))
+		}
+
+		return args.compileSuccessive(ctx, format, pointer)
 	}
 
-	return format, values, offset
+	return format, offset
 }
 
-func (args *Args) compileSuccessive(buf *strings.Builder, format string, values []interface{}, offset int) (string, []interface{}, int) {
+func (args *Args) compileSuccessive(ctx *compileContext, format string, offset int) (string, int) {
 	if offset >= len(args.args) {
-		return format, values, offset
+		return format, offset
 	}
 
-	arg := args.args[offset]
-	values = args.compileArg(buf, values, arg)
+	args.compileIndex(ctx, offset)
 
-	return format, values, offset + 1
+	return format, offset + 1
 }
 
-func (args *Args) compileArg(buf *strings.Builder, values []interface{}, arg interface{}) []interface{} {
+// errBuilder is a Builder reporting errors in BuildE.
+type errBuilder interface {
+	BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error)
+}
+
+func (args *Args) compileArg(ctx *compileContext, arg interface{}) {
 	switch a := arg.(type) {
+	case errBuilder:
+		s, values, err := a.BuildE(ctx.values...)
+		ctx.values = values
+		ctx.buf.WriteString(s)
+
+		if err != nil {
+			ctx.errs = append(ctx.errs, err)
+		}
 	case Builder:
 		var s string
-		s, values = a.Build(values...)
-		buf.WriteString(s)
+		s, ctx.values = a.Build(ctx.values...)
+		ctx.buf.WriteString(s)
 	case rawArgs:
-		buf.WriteString(a.expr)
+		ctx.buf.WriteString(a.expr)
+	case castArgs:
+		if _, ok := a.arg.(Builder); ok {
+			ctx.buf.WriteString("(")
+			args.compileArg(ctx, a.arg)
+			ctx.buf.WriteString(")")
+		} else {
+			args.compileArg(ctx, a.arg)
+		}
+
+		ctx.buf.WriteString("::")
+		ctx.buf.WriteString(a.typ)
 	case listArgs:
 		if len(a.args) > 0 {
-			values = args.compileArg(buf, values, a.args[0])
+			args.compileArg(ctx, a.args[0])
 		}
 
 		for i := 1; i < len(a.args); i++ {
-			buf.WriteString(", ")
-			values = args.compileArg(buf, values, a.args[i])
+			ctx.buf.WriteString(", ")
+			args.compileArg(ctx, a.args[i])
 		}
 	default:
-		fmt.Fprintf(buf, "$%d", len(values)+1)
+		fmt.Fprintf(&ctx.buf, "$%d", len(ctx.values)+1)
 
-		values = append(values, arg)
+		ctx.values = append(ctx.values, arg)
 	}
-
-	return values
 }
@@ -1,5 +1,28 @@
 package pgsql
 
+import (
+	"errors"
+)
+
+// Errors reported by `BuildE`, `Validate` and `Args#CompileE`.
+var (
+	ErrMissingTable   = errors.New("pgsql: missing table")
+	ErrMissingColumns = errors.New("pgsql: missing columns")
+	ErrMissingValues  = errors.New("pgsql: missing values")
+	ErrMissingQuery   = errors.New("pgsql: missing query")
+	ErrMissingWhere   = errors.New("pgsql: missing WHERE")
+	ErrEmptySet       = errors.New("pgsql: empty SET")
+	ErrValueCount     = errors.New("pgsql: value count mismatch")
+	ErrUnresolvedName = errors.New("pgsql: unresolved named argument")
+	ErrArgOutOfRange  = errors.New("pgsql: argument out of range")
+	ErrDuplicateName  = errors.New("pgsql: named argument added with different values")
+
+	ErrDistinctOnOrderBy     = errors.New("pgsql: DISTINCT ON expressions must match leftmost ORDER BY expressions")
+	ErrMissingConflictAction = errors.New("pgsql: missing ON CONFLICT action")
+	ErrMissingConflictTarget = errors.New("pgsql: missing ON CONFLICT target")
+	ErrInvalidClause         = errors.New("pgsql: invalid clause")
+)
+
 // Builder is a general SQL builder.
 // It's used by Args to create nested SQL like the `IN` expression in
 // `SELECT * FROM t1 WHERE id IN (SELECT id FROM t2)`.
@@ -16,6 +39,24 @@ func (cb *builder) Build(initialArg ...interface{}) (sql string, args []interfac
 	return cb.args.Compile(cb.format, initialArg...)
 }
 
+// BuildE is like Build but reports errors found in compiling the format.
+// See doc in `Args#CompileE` for details.
+func (cb *builder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	return cb.args.CompileE(cb.format, initialArg...)
+}
+
+// BuildE builds builder and reports errors if builder supports `BuildE`,
+// e.g. builders created by `Build` or `NewSelectBuilder`.
+// Otherwise, it returns result of `Build` without error.
+func BuildE(builder Builder, initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	if eb, ok := builder.(errBuilder); ok {
+		return eb.BuildE(initialArg...)
+	}
+
+	sql, args = builder.Build(initialArg...)
+	return
+}
+
 // Build creates a Builder from a format string.
 // The format string uses special syntax to represent arguments.
 // See doc in `Args#Compile` for syntax details.
@@ -31,3 +72,21 @@ func Build(format string, arg ...interface{}) Builder {
 		format: format,
 	}
 }
+
+// BuildNamed creates a Builder from a format string.
+// The format string uses `${key}` to refer the value of named by key.
+// Every `${key}` referring the same key shares one placeholder.
+func BuildNamed(format string, named map[string]interface{}) Builder {
+	args := &Args{
+		onlyNamed: true,
+	}
+
+	for n, v := range named {
+		args.Add(Named(n, v))
+	}
+
+	return &builder{
+		args:   args,
+		format: format,
+	}
+}
@@ -0,0 +1,185 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild1(t *testing.T) {
+	result, args := Build("SELECT * FROM demo.user WHERE id = $? AND status IN ($?)", 1234, List([]int{1, 2})).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = $1 AND status IN ($2, $3)", result)
+	assert.Equal(t, []interface{}{1234, 1, 2}, args)
+}
+
+func TestBuildNamed1(t *testing.T) {
+	result, args := Build(
+		"SELECT * FROM demo.orders WHERE status = $? AND tenant_id = ${tenant} AND created_by IN (SELECT id FROM demo.user WHERE tenant_id = ${tenant})",
+		"paid",
+		Named("tenant", 42),
+	).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.orders WHERE status = $1 AND tenant_id = $2 AND created_by IN (SELECT id FROM demo.user WHERE tenant_id = $2)", result)
+	assert.Equal(t, []interface{}{"paid", 42}, args)
+}
+
+func TestBuildNamed2(t *testing.T) {
+	result, args := BuildNamed(
+		"SELECT * FROM demo.user WHERE tenant_id = ${tenant} AND (name = ${name} OR nick = ${name}) AND tenant_id <> ${tenant} AND $1 = $$1",
+		map[string]interface{}{
+			"tenant": 42,
+			"name":   "Huan Du",
+		},
+	).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.user WHERE tenant_id = $1 AND (name = $2 OR nick = $2) AND tenant_id <> $1 AND $1 = $1", result)
+	assert.Equal(t, []interface{}{42, "Huan Du"}, args)
+}
+
+func TestBuildNamed3(t *testing.T) {
+	sb := Select("id").From("demo.user")
+	sb.Where(sb.EQ("status", 1))
+
+	result, args := Build("SELECT * FROM demo.orders WHERE $? AND user_id IN (${users}) AND referrer_id IN (${users})", Raw("TRUE"), Named("users", sb)).Build(10)
+
+	assert.Equal(t, "SELECT * FROM demo.orders WHERE TRUE AND user_id IN (SELECT id FROM demo.user WHERE status = $2) AND referrer_id IN (SELECT id FROM demo.user WHERE status = $2)", result)
+	assert.Equal(t, []interface{}{10, 1}, args)
+}
+
+func TestBuildSharedArg(t *testing.T) {
+	result, args := Build("SELECT * FROM demo.user WHERE id = $0 OR parent_id = $0 OR name = $1", 1, "a").Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = $1 OR parent_id = $1 OR name = $2", result)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+}
+
+func TestBuildNamedCond(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.orders").Where(
+		sb.EQ("tenant_id", Named("tenant", 42)),
+		sb.EQ("status", "paid"),
+		sb.NE("owner_tenant_id", Named("tenant", 42)),
+	)
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.orders WHERE tenant_id = $1 AND status = $2 AND owner_tenant_id <> $1", result)
+	assert.Equal(t, []interface{}{42, "paid"}, args)
+
+	sb = NewSelectBuilder()
+	sb.Select("*").From("demo.orders").Where(
+		sb.EQ("a", Named("x", 1)),
+		sb.EQ("b", Named("x", 2)),
+	)
+
+	_, _, err := sb.BuildE()
+	assert.ErrorIs(t, err, ErrDuplicateName)
+	assert.Contains(t, err.Error(), "x")
+
+	_, _, err = sb.Clone().BuildE()
+	assert.ErrorIs(t, err, ErrDuplicateName)
+}
+
+func TestBuildE(t *testing.T) {
+	_, _, err := BuildE(Build("SELECT * FROM demo.user WHERE id = $? AND status = $?", 1))
+	assert.ErrorIs(t, err, ErrArgOutOfRange)
+
+	_, _, err = BuildE(Build("SELECT * FROM demo.user WHERE id = $3", 1))
+	assert.ErrorIs(t, err, ErrArgOutOfRange)
+
+	_, _, err = BuildE(BuildNamed("SELECT * FROM demo.user WHERE id = ${id} AND name = ${name}", map[string]interface{}{"id": 1}))
+	assert.ErrorIs(t, err, ErrUnresolvedName)
+	assert.Contains(t, err.Error(), "${name}")
+
+	result, args, err := BuildE(Build("SELECT * FROM demo.user WHERE id = $?", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = $1", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestValidate(t *testing.T) {
+	_, _, err := NewSelectBuilder().From("demo.user").BuildE()
+	assert.ErrorIs(t, err, ErrMissingColumns)
+
+	result, _, err := Select("1").BuildE()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1", result)
+
+	err = NewInsertBuilder().Cols("id", "name").Values(1).Values(2, "b").Validate()
+	assert.ErrorIs(t, err, ErrMissingTable)
+	assert.ErrorIs(t, err, ErrValueCount)
+	assert.Contains(t, err.Error(), "row 0 has 1 values for 2 columns")
+
+	assert.ErrorIs(t, InsertInto("demo.user").Values(1).Values(1, 2).Validate(), ErrValueCount)
+	assert.ErrorIs(t, InsertInto("demo.user").Cols("id").Validate(), ErrMissingValues)
+	assert.NoError(t, InsertInto("demo.user").DefaultValues().Validate())
+
+	assert.ErrorIs(t, Update("demo.user").Validate(), ErrEmptySet)
+	assert.NoError(t, Update("demo.user").Set("status = 1").Validate())
+	assert.ErrorIs(t, Update("demo.user").Set("status = 1").RequireWhere().Validate(), ErrMissingWhere)
+	assert.ErrorIs(t, NewUpdateBuilder().Set("status = 1").Validate(), ErrMissingTable)
+
+	assert.NoError(t, DeleteFrom("demo.user").Validate())
+	assert.ErrorIs(t, DeleteFrom("demo.user").RequireWhere().Validate(), ErrMissingWhere)
+	assert.NoError(t, DeleteFrom("demo.user").RequireWhere().Where("id = 1").Validate())
+
+	assert.ErrorIs(t, NewUnionBuilder().Validate(), ErrMissingQuery)
+	assert.ErrorIs(t, With().Validate(), ErrMissingQuery)
+	assert.ErrorIs(t, CTEQuery("t").Validate(), ErrMissingQuery)
+}
+
+func TestValidateClauses(t *testing.T) {
+	assert.NoError(t, Select("count(*)").From("demo.user").Having("count(*) > 1").Validate())
+	assert.ErrorIs(t, Select("count(*)").From("demo.user").Having("count(*) > 1").ForUpdate().Validate(), ErrInvalidClause)
+	assert.ErrorIs(t, Select("*").From("demo.user").Of("user").Validate(), ErrInvalidClause)
+	assert.ErrorIs(t, Select("*").From("demo.user").SkipLocked().Validate(), ErrInvalidClause)
+	assert.ErrorIs(t, Select("kind").From("demo.user").GroupBy("kind").ForUpdate().Validate(), ErrInvalidClause)
+	assert.ErrorIs(t, Select("name").From("demo.user").Distinct().ForShare().Validate(), ErrInvalidClause)
+	assert.NoError(t, Select("*").From("demo.user").ForUpdate().Of("user").SkipLocked().Validate())
+	assert.NoError(t, Select("kind").From("demo.user").GroupBy("kind").Having("count(*) > 1").Validate())
+
+	assert.ErrorIs(t, InsertInto("demo.user").Cols("id").DefaultValues().Validate(), ErrInvalidClause)
+	assert.ErrorIs(t, InsertInto("demo.user").Values(1).Select(Select("1")).Validate(), ErrInvalidClause)
+	assert.NoError(t, InsertInto("demo.user").Cols("id").Select(Select("1")).Validate())
+
+	assert.ErrorIs(t, Update("demo.user").Set("status = 1").KeyCols("id").Validate(), ErrInvalidClause)
+	assert.NoError(t, Update("demo.user").Set("status = 1").KeyCols("id").Limit(1).Validate())
+	assert.ErrorIs(t, DeleteFrom("demo.user").KeyCols("id").Validate(), ErrInvalidClause)
+	assert.NoError(t, DeleteFrom("demo.user").KeyCols("id").OrderBy("id").Validate())
+}
+
+func TestBuildENested(t *testing.T) {
+	sub := NewSelectBuilder().From("demo.user")
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.orders").Where(sb.In("user_id", sub), sb.EQ("status", 1))
+
+	result, args, err := sb.BuildE()
+	assert.ErrorIs(t, err, ErrMissingColumns)
+	assert.Equal(t, "SELECT * FROM demo.orders WHERE user_id IN (SELECT  FROM demo.user) AND status = $1", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestArgsClone(t *testing.T) {
+	sub := Select("id").From("demo.user")
+	args := &Args{}
+	args.Add(Named("status", 1))
+	args.Add(sub)
+
+	clone := args.Clone()
+	clone.Add(2)
+	sub.Where("id > 10")
+
+	result, values := args.Compile("SELECT * FROM t WHERE status = ${status} AND id IN ($1)")
+	assert.Equal(t, "SELECT * FROM t WHERE status = $1 AND id IN (SELECT id FROM demo.user WHERE id > 10)", result)
+	assert.Equal(t, []interface{}{1}, values)
+
+	result, values = clone.Compile("SELECT * FROM t WHERE status = ${status} AND id IN ($1) AND kind = $2")
+	assert.Equal(t, "SELECT * FROM t WHERE status = $1 AND id IN (SELECT id FROM demo.user) AND kind = $2", result)
+	assert.Equal(t, []interface{}{1, 2}, values)
+}
+
+func TestCast(t *testing.T) {
+	sub := Select("max(id)").From("demo.user")
+	result, args := Build("SELECT $?, $?", Cast(1, "bigint"), Cast(sub, "int")).Build()
+	assert.Equal(t, "SELECT $1::bigint, (SELECT max(id) FROM demo.user)::int", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
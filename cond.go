@@ -1,6 +1,10 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -74,7 +78,13 @@ func (c *Cond) LE(field string, value interface{}) string {
 }
 
 // In represents "field IN (value...)".
+// If there are more values than the threshold set by `Args#SetInArrayThreshold`,
+// it's "field = ANY($1)" with values in one array.
 func (c *Cond) In(field string, value ...interface{}) string {
+	if array, ok := useArray(c.Args.inArrayThreshold, value); ok {
+		return c.InArray(field, array)
+	}
+
 	vs := make([]string, 0, len(value))
 
 	for _, v := range value {
@@ -90,7 +100,13 @@ func (c *Cond) In(field string, value ...interface{}) string {
 }
 
 // NotIn represents "field NOT IN (value...)".
+// If there are more values than the threshold set by `Args#SetInArrayThreshold`,
+// it's "field <> ALL($1)" with values in one array.
 func (c *Cond) NotIn(field string, value ...interface{}) string {
+	if array, ok := useArray(c.Args.inArrayThreshold, value); ok {
+		return c.NotInArray(field, array)
+	}
+
 	vs := make([]string, 0, len(value))
 
 	for _, v := range value {
@@ -105,6 +121,44 @@ func (c *Cond) NotIn(field string, value ...interface{}) string {
 	return buf.String()
 }
 
+// InArray represents "field = ANY($1)" with array as one parameter,
+// which works like IN but keeps the same SQL regardless of the number of values.
+// The array is usually a slice like `[]int64{1, 2, 3}`.
+// Use `Cast` to set its type if needed, e.g. `InArray("id", Cast(ids, "bigint[]"))`.
+func (c *Cond) InArray(field string, array interface{}) string {
+	return c.AnyArray(field, "=", array)
+}
+
+// NotInArray represents "field <> ALL($1)" with array as one parameter,
+// which works like NOT IN.
+func (c *Cond) NotInArray(field string, array interface{}) string {
+	buf := &strings.Builder{}
+	buf.WriteString(field)
+	buf.WriteString(" <> ALL(")
+	buf.WriteString(c.Args.Add(array))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+// AnyArray represents "field op ANY($1)" with array as one parameter.
+func (c *Cond) AnyArray(field, op string, array interface{}) string {
+	return c.quantifiedArray(field, op, "ANY", array)
+}
+
+// quantifiedArray represents "field op quantifier($1)" with array as one parameter.
+func (c *Cond) quantifiedArray(field, op, quantifier string, array interface{}) string {
+	buf := &strings.Builder{}
+	buf.WriteString(field)
+	buf.WriteString(" ")
+	buf.WriteString(op)
+	buf.WriteString(" ")
+	buf.WriteString(quantifier)
+	buf.WriteString("(")
+	buf.WriteString(c.Args.Add(array))
+	buf.WriteString(")")
+	return buf.String()
+}
+
 // Like represents "field LIKE value".
 func (c *Cond) Like(field string, value interface{}) string {
 	buf := &strings.Builder{}
@@ -197,58 +251,277 @@ func (c *Cond) NotExists(subquery interface{}) string {
 	return buf.String()
 }
 
-// Any represents "field op ANY (value...)".
+// Any represents "field op ANY(array)" or "field op ANY(subquery)".
+// See `Some` for how value is used.
 func (c *Cond) Any(field, op string, value ...interface{}) string {
-	vs := make([]string, 0, len(value))
+	return c.quantified(field, op, "ANY", value)
+}
 
-	for _, v := range value {
-		vs = append(vs, c.Args.Add(v))
+// All represents "field op ALL(array)" or "field op ALL(subquery)".
+// See `Some` for how value is used.
+func (c *Cond) All(field, op string, value ...interface{}) string {
+	return c.quantified(field, op, "ALL", value)
+}
+
+// Some represents "field op SOME(array)" or "field op SOME(subquery)".
+//
+// PostgreSQL requires an array or a subquery in SOME, ANY and ALL, so value is used as following.
+//
+//	a Builder                 field op SOME(SELECT ...)
+//	a slice or an array       field op SOME($1) with value as one parameter
+//	a value created by Cast   field op SOME($1::typ), e.g. `Cast(tags, "text[]")`
+//	other values              field op SOME(ARRAY[$1, $2])
+//	no value                  field op SOME('{}')
+//
+// Like `In`, values of the same type are sent as one typed slice, e.g. `[]int{1, 2}` in SOME($1),
+// if there are more values than the threshold set by `Args#SetInArrayThreshold`.
+func (c *Cond) Some(field, op string, value ...interface{}) string {
+	return c.quantified(field, op, "SOME", value)
+}
+
+func (c *Cond) quantified(field, op, quantifier string, value []interface{}) string {
+	if len(value) == 1 {
+		switch value[0].(type) {
+		case Builder, castArgs:
+			return c.quantifiedArray(field, op, quantifier, value[0])
+		}
+
+		if isListValue(value[0]) {
+			return c.quantifiedArray(field, op, quantifier, value[0])
+		}
+	}
+
+	if array, ok := useArray(c.Args.inArrayThreshold, value); ok {
+		return c.quantifiedArray(field, op, quantifier, array)
 	}
 
 	buf := &strings.Builder{}
 	buf.WriteString(field)
 	buf.WriteString(" ")
 	buf.WriteString(op)
-	buf.WriteString(" ANY (")
-	buf.WriteString(strings.Join(vs, ", "))
-	buf.WriteString(")")
-	return buf.String()
-}
+	buf.WriteString(" ")
+	buf.WriteString(quantifier)
+
+	if len(value) == 0 {
+		buf.WriteString("('{}')")
+		return buf.String()
+	}
 
-// All represents "field op ALL (value...)".
-func (c *Cond) All(field, op string, value ...interface{}) string {
 	vs := make([]string, 0, len(value))
 
 	for _, v := range value {
 		vs = append(vs, c.Args.Add(v))
 	}
 
-	buf := &strings.Builder{}
-	buf.WriteString(field)
-	buf.WriteString(" ")
-	buf.WriteString(op)
-	buf.WriteString(" ALL (")
+	buf.WriteString("(ARRAY[")
 	buf.WriteString(strings.Join(vs, ", "))
-	buf.WriteString(")")
+	buf.WriteString("])")
 	return buf.String()
 }
 
-// Some represents "field op SOME (value...)".
-func (c *Cond) Some(field, op string, value ...interface{}) string {
-	vs := make([]string, 0, len(value))
+// ErrUnknownOperator is returned by `Cond#EqMap` for an unknown operator.
+var ErrUnknownOperator = errors.New("pgsql: unknown operator")
+
+// Operators in the map values of `EqMap`.
+const (
+	OpEq    = "$eq"
+	OpNe    = "$ne"
+	OpGt    = "$gt"
+	OpGte   = "$gte"
+	OpLt    = "$lt"
+	OpLte   = "$lte"
+	OpLike  = "$like"
+	OpILike = "$ilike"
+	OpIn    = "$in"
+	OpNin   = "$nin"
+)
 
-	for _, v := range value {
-		vs = append(vs, c.Args.Add(v))
+var mapOps = map[string]string{
+	OpGt:    ">",
+	OpGte:   ">=",
+	OpLt:    "<",
+	OpLte:   "<=",
+	OpLike:  "LIKE",
+	OpILike: "ILIKE",
+}
+
+// EqMap represents AND logic of conditions on fields in m, sorted by field.
+//
+// Field names are quoted as identifiers split by dots, e.g. "u.status" is `"u"."status"`,
+// so that they can come from user input like filters in a query string.
+// A name created by `Ident` is used as it is.
+// A condition is built according to the type of value.
+//
+//	nil, nil pointer, nil slice            field IS NULL
+//	slice or array except []byte           field = ANY($1)
+//	map[string]interface{} of operators    field > $1 AND field < $2
+//	others                                 field = $1
+//
+// Operators are OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpLike, OpILike, OpIn and OpNin,
+// e.g. `{"age": {"$gte": 18, "$lt": 65}, "role": {"$nin": []string{"guest"}}}`.
+// A nil value is IS NULL in OpEq and OpIn, and IS NOT NULL in OpNe and OpNin.
+// OpIn and OpNin are "= ANY" and "<> ALL" for slices or arrays, and "=" and "<>" for others.
+//
+// It reports ErrUnknownOperator for an unknown operator.
+// It's "TRUE" if m is empty.
+func (c *Cond) EqMap(m map[string]interface{}) (string, error) {
+	fields := make([]string, 0, len(m))
+
+	for field := range m {
+		fields = append(fields, field)
 	}
 
-	buf := &strings.Builder{}
-	buf.WriteString(field)
-	buf.WriteString(" ")
-	buf.WriteString(op)
-	buf.WriteString(" SOME (")
-	buf.WriteString(strings.Join(vs, ", "))
-	buf.WriteString(")")
-	return buf.String()
+	sort.Strings(fields)
+	exprs := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		value := m[field]
+		col := quoteIdentName(true, field)
+		ops, ok := value.(map[string]interface{})
+
+		if !ok {
+			exprs = append(exprs, c.eq(col, value))
+			continue
+		}
+
+		names := make([]string, 0, len(ops))
+
+		for name := range ops {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			expr, err := c.mapOp(col, name, ops[name])
+
+			if err != nil {
+				return "", err
+			}
+
+			exprs = append(exprs, expr)
+		}
+	}
+
+	return c.andAll(exprs), nil
+}
+
+// EqStruct represents AND logic of equalities on columns mapped by `Struct` in value,
+// in the same order as `Struct#Columns`.
+// Columns with `omitempty` are skipped if their fields are zero values.
+// Conditions are built in the same way as non-map values in `EqMap`.
+//
+// It's "TRUE" if there is no column or value is not a struct.
+func (c *Cond) EqStruct(value interface{}) string {
+	s := structOf(value)
+	rv, ok := s.structValue(value)
+
+	if !ok {
+		return "TRUE"
+	}
+
+	exprs := make([]string, 0, len(s.fields))
+
+	for _, f := range s.fields {
+		if f.omitEmpty && isEmptyField(rv, f.index) {
+			continue
+		}
+
+		exprs = append(exprs, c.eq(Escape(f.col), fieldValue(rv, f.index)))
+	}
+
+	return c.andAll(exprs)
+}
+
+func (c *Cond) mapOp(field, name string, value interface{}) (string, error) {
+	switch name {
+	case OpEq:
+		return c.eq(field, value), nil
+	case OpNe:
+		if isNilValue(value) {
+			return c.IsNotNull(field), nil
+		}
+
+		return c.NE(field, value), nil
+	case OpIn:
+		if isNilValue(value) {
+			return c.IsNull(field), nil
+		}
+
+		if isListValue(value) {
+			return c.InArray(field, value), nil
+		}
+
+		return c.EQ(field, value), nil
+	case OpNin:
+		if isNilValue(value) {
+			return c.IsNotNull(field), nil
+		}
+
+		if isListValue(value) {
+			return c.NotInArray(field, value), nil
+		}
+
+		return c.NE(field, value), nil
+	}
+
+	op, ok := mapOps[name]
+
+	if !ok {
+		return "", fmt.Errorf("%w %v on %v", ErrUnknownOperator, name, field)
+	}
+
+	return c.Expr(field, op, value), nil
+}
+
+func (c *Cond) eq(field string, value interface{}) string {
+	if isNilValue(value) {
+		return c.IsNull(field)
+	}
+
+	if isListValue(value) {
+		return c.InArray(field, value)
+	}
+
+	return c.EQ(field, value)
+}
+
+func (c *Cond) andAll(exprs []string) string {
+	switch len(exprs) {
+	case 0:
+		return "TRUE"
+	case 1:
+		return exprs[0]
+	}
+
+	return c.And(exprs...)
+}
+
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Slice:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// isListValue reports whether value is a slice or an array except []byte.
+func isListValue(value interface{}) bool {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	}
+
+	return false
 }
 
 // Var returns a placeholder for value.
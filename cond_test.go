@@ -0,0 +1,157 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondEqMap(t *testing.T) {
+	sb := NewSelectBuilder()
+	where, err := sb.EqMap(map[string]interface{}{
+		"status":     1,
+		"deleted_at": nil,
+		"role":       []string{"admin", "owner"},
+		"age":        map[string]interface{}{OpGte: 18, OpLt: 65},
+		"name":       map[string]interface{}{OpILike: "a%", OpNe: nil},
+		"kind":       map[string]interface{}{OpNin: []string{"bot"}, OpIn: "user"},
+		"parent_id":  map[string]interface{}{OpEq: nil},
+	})
+
+	assert.NoError(t, err)
+	sb.Select("*").From("demo.user").Where(where)
+
+	result, args := sb.Build()
+	assert.Equal(t, `SELECT * FROM demo.user WHERE ("age" >= $1 AND "age" < $2 AND "deleted_at" IS NULL AND "kind" = $3 AND "kind" <> ALL($4) AND "name" ILIKE $5 AND "name" IS NOT NULL AND "parent_id" IS NULL AND "role" = ANY($6) AND "status" = $7)`, result)
+	assert.Equal(t, []interface{}{18, 65, "user", []string{"bot"}, "a%", []string{"admin", "owner"}, 1}, args)
+
+	where, err = sb.EqMap(map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `"id" = $7`, where)
+
+	where, err = sb.EqMap(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUE", where)
+
+	_, err = sb.EqMap(map[string]interface{}{"id": map[string]interface{}{"$regex": "a"}})
+	assert.ErrorIs(t, err, ErrUnknownOperator)
+}
+
+func TestCondEqMapEscape(t *testing.T) {
+	sb := NewSelectBuilder()
+	where, err := sb.EqMap(map[string]interface{}{
+		"price$":              1,
+		"owner":               map[string]interface{}{OpIn: nil},
+		"team_id":             map[string]interface{}{OpNin: (*int)(nil)},
+		"1=1 OR id":           2,
+		"i.tag_id":            []int(nil),
+		Ident("i", "kind_id"): map[string]interface{}{OpNe: []int(nil)},
+	})
+
+	assert.NoError(t, err)
+	sb.Select("*").From("demo.item i").Where(where)
+
+	result, args := sb.Build()
+	assert.Equal(t, `SELECT * FROM demo.item i WHERE ("i"."kind_id" IS NOT NULL AND "1=1 OR id" = $1 AND "i"."tag_id" IS NULL AND "owner" IS NULL AND "price$" = $2 AND "team_id" IS NOT NULL)`, result)
+	assert.Equal(t, []interface{}{2, 1}, args)
+}
+
+func TestCondEqStruct(t *testing.T) {
+	type filter struct {
+		Status   int      `db:"status"`
+		Role     []string `db:"role"`
+		ParentID *int     `db:"parent_id"`
+		Name     string   `db:"name,omitempty"`
+		Ignored  string   `db:"-"`
+	}
+
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.user").Where(sb.EqStruct(&filter{Status: 1, Role: []string{"admin"}}))
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE (status = $1 AND role = ANY($2) AND parent_id IS NULL)", result)
+	assert.Equal(t, []interface{}{1, []string{"admin"}}, args)
+
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user").Set(ub.Assign("visited", 0)).Where(ub.EqStruct(filter{Name: "a", Role: []string{}}))
+
+	result, args = ub.Build()
+	assert.Equal(t, "UPDATE demo.user SET visited = $1 WHERE (status = $2 AND role = ANY($3) AND parent_id IS NULL AND name = $4)", result)
+	assert.Equal(t, []interface{}{0, 0, []string{}, "a"}, args)
+
+	assert.Equal(t, "TRUE", sb.EqStruct(1))
+	assert.Same(t, structOf(filter{}), structOf(&filter{}))
+}
+
+func TestCondInArray(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.user").Where(
+		sb.InArray("id", []int64{1, 2, 3}),
+		sb.NotInArray("role", Cast([]string{"bot"}, "text[]")),
+		sb.AnyArray("score", ">", []int{90}),
+	)
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = ANY($1) AND role <> ALL($2::text[]) AND score > ANY($3)", result)
+	assert.Equal(t, []interface{}{[]int64{1, 2, 3}, []string{"bot"}, []int{90}}, args)
+}
+
+func TestCondInThreshold(t *testing.T) {
+	t.Parallel()
+
+	sb := NewSelectBuilder().InArrayThreshold(2)
+	sb.Select("*").From("demo.user").Where(
+		sb.In("id", 1, 2),
+		sb.In("id", 1, 2, 3),
+		sb.NotIn("kind", "a", "b", "c"),
+		sb.In("status", 1, "2", 3),
+		sb.In("parent_id", 1, nil, 3),
+	).WhereExpr(InArray("owner_id", []int64{1, 2, 3}), NotIn("owner_id", 4, 5, 6))
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id IN ($1, $2) AND id = ANY($3) AND kind <> ALL($4) AND status IN ($5, $6, $7) AND parent_id IN ($8, $9, $10) AND owner_id = ANY($11) AND owner_id NOT IN ($12, $13, $14)", result)
+	assert.Equal(t, []interface{}{1, 2, []int{1, 2, 3}, []string{"a", "b", "c"}, 1, "2", 3, 1, nil, 3, []int64{1, 2, 3}, 4, 5, 6}, args)
+
+	clone := sb.Clone()
+	clone.Where(clone.In("id", 4, 5, 6))
+	result, _ = clone.Build()
+	assert.Contains(t, result, "AND id = ANY($15)")
+
+	other := NewSelectBuilder()
+	other.Select("*").From("demo.user").Where(other.In("id", 1, 2, 3))
+	result, _ = other.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id IN ($1, $2, $3)", result)
+
+	ub := NewUpdateBuilder().InArrayThreshold(1)
+	ub.Update("demo.user").Set(ub.Assign("status", 0)).Where(ub.In("id", 1, 2))
+	result, _ = ub.Build()
+	assert.Equal(t, "UPDATE demo.user SET status = $1 WHERE id = ANY($2)", result)
+}
+
+func TestCondAnyAllSome(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.post").Where(
+		sb.Any("tags", "=", "go"),
+		sb.Any("id", "=", 1, 2, 3),
+		sb.All("score", ">", []float64{1.5, 2.5}),
+		sb.Some("tag", "=", Cast([]string{"a", "b"}, "text[]")),
+		sb.Any("author_id", "=", Select("id").From("demo.user").Where("status = 1")),
+		sb.All("kind", "<>", "a", 1),
+		sb.Any("owner_id", "="),
+	)
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.post WHERE tags = ANY(ARRAY[$1]) AND id = ANY(ARRAY[$2, $3, $4]) AND score > ALL($5) AND tag = SOME($6::text[]) AND author_id = ANY(SELECT id FROM demo.user WHERE status = 1) AND kind <> ALL(ARRAY[$7, $8]) AND owner_id = ANY('{}')", result)
+	assert.Equal(t, []interface{}{"go", 1, 2, 3, []float64{1.5, 2.5}, []string{"a", "b"}, "a", 1}, args)
+
+	sb = NewSelectBuilder().InArrayThreshold(2)
+	sb.Select("*").From("demo.post").Where(
+		sb.Any("id", "=", 1, 2),
+		sb.Any("id", "=", 1, 2, 3),
+		sb.All("kind", "<>", "a", 1, "b"),
+	)
+
+	result, args = sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.post WHERE id = ANY(ARRAY[$1, $2]) AND id = ANY($3) AND kind <> ALL(ARRAY[$4, $5, $6])", result)
+	assert.Equal(t, []interface{}{1, 2, []int{1, 2, 3}, "a", 1, "b"}, args)
+}
@@ -0,0 +1,225 @@
+package pgsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewCTEBuilder creates a new WITH builder.
+func NewCTEBuilder() *CTEBuilder {
+	return newCTEBuilder()
+}
+
+func newCTEBuilder() *CTEBuilder {
+	return &CTEBuilder{
+		args: &Args{},
+	}
+}
+
+// CTEBuilder is a builder to build WITH clause.
+type CTEBuilder struct {
+	args      *Args
+	recursive bool
+	queries   []string
+}
+
+// With creates a WITH clause with a list of CTE queries.
+func With(queries ...*CTEQueryBuilder) *CTEBuilder {
+	return NewCTEBuilder().With(queries...)
+}
+
+// WithRecursive creates a WITH RECURSIVE clause with a list of CTE queries.
+func WithRecursive(queries ...*CTEQueryBuilder) *CTEBuilder {
+	return NewCTEBuilder().WithRecursive(queries...)
+}
+
+// With adds CTE queries to WITH clause.
+func (cteb *CTEBuilder) With(queries ...*CTEQueryBuilder) *CTEBuilder {
+	for _, q := range queries {
+		cteb.queries = append(cteb.queries, cteb.args.Add(q))
+	}
+
+	return cteb
+}
+
+// WithRecursive adds CTE queries to WITH clause and marks it as RECURSIVE.
+func (cteb *CTEBuilder) WithRecursive(queries ...*CTEQueryBuilder) *CTEBuilder {
+	cteb.recursive = true
+	return cteb.With(queries...)
+}
+
+// Select creates a SELECT builder using this WITH clause.
+func (cteb *CTEBuilder) Select(col ...string) *SelectBuilder {
+	return newSelectBuilder().With(cteb).Select(col...)
+}
+
+// InsertInto creates an INSERT builder using this WITH clause.
+func (cteb *CTEBuilder) InsertInto(table string) *InsertBuilder {
+	return newInsertBuilder().With(cteb).InsertInto(table)
+}
+
+// Update creates an UPDATE builder using this WITH clause.
+func (cteb *CTEBuilder) Update(table string) *UpdateBuilder {
+	return newUpdateBuilder().With(cteb).Update(table)
+}
+
+// DeleteFrom creates a DELETE builder using this WITH clause.
+func (cteb *CTEBuilder) DeleteFrom(table string) *DeleteBuilder {
+	return newDeleteBuilder().With(cteb).DeleteFrom(table)
+}
+
+// Clone returns a deep copy of cteb.
+func (cteb *CTEBuilder) Clone() *CTEBuilder {
+	clone := *cteb
+	clone.args = cteb.args.Clone()
+	clone.queries = cloneStrings(cteb.queries)
+	return &clone
+}
+
+func (cteb *CTEBuilder) cloneBuilder() Builder {
+	return cteb.Clone()
+}
+
+// Build returns compiled WITH clause and args.
+func (cteb *CTEBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
+	return cteb.args.Compile(cteb.format(), initialArg...)
+}
+
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (cteb *CTEBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = cteb.args.CompileE(cteb.format(), initialArg...)
+	return sql, args, errors.Join(cteb.Validate(), err)
+}
+
+// Validate reports a WITH clause without any query.
+func (cteb *CTEBuilder) Validate() error {
+	if len(cteb.queries) == 0 {
+		return fmt.Errorf("%w in WITH", ErrMissingQuery)
+	}
+
+	return nil
+}
+
+// format returns WITH clause in the format of `Args#Compile`.
+func (cteb *CTEBuilder) format() string {
+	buf := &strings.Builder{}
+	buf.WriteString("WITH ")
+
+	if cteb.recursive {
+		buf.WriteString("RECURSIVE ")
+	}
+
+	buf.WriteString(strings.Join(cteb.queries, ", "))
+
+	return buf.String()
+}
+
+// NewCTEQueryBuilder creates a new CTE query builder.
+func NewCTEQueryBuilder() *CTEQueryBuilder {
+	return &CTEQueryBuilder{
+		args: &Args{},
+	}
+}
+
+// CTEQueryBuilder is a builder to build a single query in WITH clause.
+type CTEQueryBuilder struct {
+	args         *Args
+	name         string
+	cols         []string
+	materialized string
+	builderVar   string
+}
+
+// CTEQuery creates a CTE query with name and optional column names.
+func CTEQuery(name string, col ...string) *CTEQueryBuilder {
+	return NewCTEQueryBuilder().Table(name, col...)
+}
+
+// Table sets the name and optional column names of the CTE query.
+func (ctetb *CTEQueryBuilder) Table(name string, col ...string) *CTEQueryBuilder {
+	ctetb.name = name
+	ctetb.cols = col
+	return ctetb
+}
+
+// As sets the query of the CTE query.
+func (ctetb *CTEQueryBuilder) As(builder Builder) *CTEQueryBuilder {
+	ctetb.builderVar = ctetb.args.Add(builder)
+	return ctetb
+}
+
+// Materialized marks the CTE query as MATERIALIZED.
+func (ctetb *CTEQueryBuilder) Materialized() *CTEQueryBuilder {
+	ctetb.materialized = "MATERIALIZED"
+	return ctetb
+}
+
+// NotMaterialized marks the CTE query as NOT MATERIALIZED.
+func (ctetb *CTEQueryBuilder) NotMaterialized() *CTEQueryBuilder {
+	ctetb.materialized = "NOT MATERIALIZED"
+	return ctetb
+}
+
+// Clone returns a deep copy of ctetb.
+func (ctetb *CTEQueryBuilder) Clone() *CTEQueryBuilder {
+	clone := *ctetb
+	clone.args = ctetb.args.Clone()
+	clone.cols = cloneStrings(ctetb.cols)
+	return &clone
+}
+
+func (ctetb *CTEQueryBuilder) cloneBuilder() Builder {
+	return ctetb.Clone()
+}
+
+// Build returns compiled CTE query and args.
+func (ctetb *CTEQueryBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
+	return ctetb.args.Compile(ctetb.format(), initialArg...)
+}
+
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (ctetb *CTEQueryBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = ctetb.args.CompileE(ctetb.format(), initialArg...)
+	return sql, args, errors.Join(ctetb.Validate(), err)
+}
+
+// Validate reports a CTE query without name or query.
+func (ctetb *CTEQueryBuilder) Validate() error {
+	var errs []error
+
+	if ctetb.name == "" {
+		errs = append(errs, fmt.Errorf("%w in CTE query", ErrMissingTable))
+	}
+
+	if ctetb.builderVar == "" {
+		errs = append(errs, fmt.Errorf("%w in CTE query %v", ErrMissingQuery, ctetb.name))
+	}
+
+	return errors.Join(errs...)
+}
+
+// format returns CTE query in the format of `Args#Compile`.
+func (ctetb *CTEQueryBuilder) format() string {
+	buf := &strings.Builder{}
+	buf.WriteString(ctetb.name)
+
+	if len(ctetb.cols) > 0 {
+		buf.WriteString(" (")
+		buf.WriteString(strings.Join(ctetb.cols, ", "))
+		buf.WriteString(")")
+	}
+
+	buf.WriteString(" AS ")
+
+	if ctetb.materialized != "" {
+		buf.WriteString(ctetb.materialized)
+		buf.WriteRune(' ')
+	}
+
+	buf.WriteString("(")
+	buf.WriteString(ctetb.builderVar)
+	buf.WriteString(")")
+
+	return buf.String()
+}
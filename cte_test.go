@@ -0,0 +1,85 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCTE1(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id", "name").From("demo.user").Where(sb.EQ("status", 1))
+
+	result, args := With(CTEQuery("active_user").As(sb)).
+		Select("id", "name").
+		From("active_user").
+		Build()
+
+	assert.Equal(t, "WITH active_user AS (SELECT id, name FROM demo.user WHERE status = $1) SELECT id, name FROM active_user", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestCTE2(t *testing.T) {
+	base := NewSelectBuilder()
+	base.Select("id", "parent_id", "1").From("demo.category").Where(base.EQ("id", 10))
+
+	step := NewSelectBuilder()
+	step.Select("c.id", "c.parent_id", "t.depth + 1").
+		From("demo.category c").
+		Join("tree t", "c.parent_id = t.id").
+		Where(step.LT("t.depth", 5))
+
+	orders := NewSelectBuilder()
+	orders.Select("category_id", "count(*)").
+		From("demo.orders").
+		Where(orders.GT("created_at", "2023-01-01")).
+		GroupBy("category_id")
+
+	cte := WithRecursive(
+		CTEQuery("tree", "id", "parent_id", "depth").As(UnionAll(base, step)),
+		CTEQuery("stats", "category_id", "total").As(orders).NotMaterialized(),
+	)
+
+	sb := cte.Select("tree.id", "stats.total")
+	sb.From("tree").
+		LeftJoin("stats", "stats.category_id = tree.id").
+		Where(sb.GE("stats.total", 100))
+
+	result, args := sb.Build()
+
+	assert.Equal(t, "WITH RECURSIVE tree (id, parent_id, depth) AS ((SELECT id, parent_id, 1 FROM demo.category WHERE id = $1) UNION ALL (SELECT c.id, c.parent_id, t.depth + 1 FROM demo.category c JOIN tree t ON c.parent_id = t.id WHERE t.depth < $2)), stats (category_id, total) AS NOT MATERIALIZED (SELECT category_id, count(*) FROM demo.orders WHERE created_at > $3 GROUP BY category_id) SELECT tree.id, stats.total FROM tree LEFT JOIN stats ON stats.category_id = tree.id WHERE stats.total >= $4", result)
+	assert.Equal(t, []interface{}{10, 5, "2023-01-01", 100}, args)
+}
+
+func TestCTE3(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id").From("demo.user").Where(sb.LT("last_login", "2020-01-01"))
+
+	cte := With(CTEQuery("stale").As(sb).Materialized())
+
+	ub := cte.Update("demo.user")
+	ub.Set(ub.Assign("status", 0)).Where("id IN (SELECT id FROM stale)")
+
+	result, args := ub.Build()
+	assert.Equal(t, "WITH stale AS MATERIALIZED (SELECT id FROM demo.user WHERE last_login < $1) UPDATE demo.user SET status = $2 WHERE id IN (SELECT id FROM stale)", result)
+	assert.Equal(t, []interface{}{"2020-01-01", 0}, args)
+
+	db := cte.DeleteFrom("demo.session")
+	db.Where("user_id IN (SELECT id FROM stale)")
+
+	result, args = db.Build()
+	assert.Equal(t, "WITH stale AS MATERIALIZED (SELECT id FROM demo.user WHERE last_login < $1) DELETE FROM demo.session WHERE user_id IN (SELECT id FROM stale)", result)
+	assert.Equal(t, []interface{}{"2020-01-01"}, args)
+}
+
+func TestCTE4(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("max(id)").From("demo.user").Where(sb.EQ("status", 0))
+
+	ib := With(CTEQuery("last_user", "id").As(sb)).InsertInto("demo.user_log")
+	ib.Cols("user_id", "note").Values(Raw("(SELECT id FROM last_user)"), "x")
+
+	result, args := ib.Build()
+	assert.Equal(t, "WITH last_user (id) AS (SELECT max(id) FROM demo.user WHERE status = $1) INSERT INTO demo.user_log (user_id, note) VALUES ((SELECT id FROM last_user), $2)", result)
+	assert.Equal(t, []interface{}{0, "x"}, args)
+}
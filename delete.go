@@ -1,7 +1,8 @@
 package pgsql
 
 import (
-	"strconv"
+	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -25,14 +26,19 @@ func newDeleteBuilder() *DeleteBuilder {
 type DeleteBuilder struct {
 	Cond
 
-	args *Args
+	args   *Args
+	cteVar string
 
 	table       string
-	order       string
 	returning   []string
+	usingTables []string
 	whereExprs  []string
-	orderByCols []string
+	orderBy     orderByClause
+	keyCols     []string
 	limit       int
+
+	requireWhere bool
+	quoteIdents  bool
 }
 
 // DeleteFrom sets table name in DELETE.
@@ -46,27 +52,99 @@ func (db *DeleteBuilder) DeleteFrom(table string) *DeleteBuilder {
 	return db
 }
 
+// With sets the WITH clause of DELETE.
+func (db *DeleteBuilder) With(cte *CTEBuilder) *DeleteBuilder {
+	db.cteVar = db.Var(cte)
+	return db
+}
+
+// Using sets tables of USING in DELETE.
+// They can be referenced in WHERE to delete rows by joining other tables.
+func (db *DeleteBuilder) Using(table ...string) *DeleteBuilder {
+	db.usingTables = table
+	return db
+}
+
+// BuilderAs returns an AS expression wrapping a complex SQL.
+// According to SQL syntax, SQL built by builder is surrounded by parens.
+func (db *DeleteBuilder) BuilderAs(builder Builder, alias string) string {
+	return fmt.Sprintf("(%s) AS %s", db.Var(builder), alias)
+}
+
+// RequireWhere makes `BuildE` and `Validate` report ErrMissingWhere
+// if there is no WHERE expression, so that all rows are never deleted by mistake.
+func (db *DeleteBuilder) RequireWhere() *DeleteBuilder {
+	db.requireWhere = true
+	return db
+}
+
 // Where sets expressions of WHERE in DELETE.
 func (db *DeleteBuilder) Where(andExpr ...string) *DeleteBuilder {
 	db.whereExprs = append(db.whereExprs, andExpr...)
 	return db
 }
 
-// OrderBy sets columns of ORDER BY in DELETE.
+// Returning sets columns or expressions of RETURNING in DELETE.
+// Use "*" to return all columns.
+func (db *DeleteBuilder) Returning(col ...string) *DeleteBuilder {
+	db.returning = col
+	return db
+}
+
+// KeyCols sets columns identifying rows when ORDER BY or LIMIT is set.
+// The default key column is "ctid".
+// See doc in `DeleteBuilder#Build` for details.
+func (db *DeleteBuilder) KeyCols(col ...string) *DeleteBuilder {
+	db.keyCols = col
+	return db
+}
+
+// OrderBy adds columns of ORDER BY in DELETE.
+// Use Asc, Desc, NullsFirst or NullsLast to set sort options of these columns.
 func (db *DeleteBuilder) OrderBy(col ...string) *DeleteBuilder {
-	db.orderByCols = col
+	db.orderBy.add("", col...)
+	return db
+}
+
+// OrderByAsc adds columns of ORDER BY ASC in DELETE.
+func (db *DeleteBuilder) OrderByAsc(col ...string) *DeleteBuilder {
+	db.orderBy.add("ASC", col...)
 	return db
 }
 
-// Asc sets order of ORDER BY to ASC.
+// OrderByDesc adds columns of ORDER BY DESC in DELETE.
+func (db *DeleteBuilder) OrderByDesc(col ...string) *DeleteBuilder {
+	db.orderBy.add("DESC", col...)
+	return db
+}
+
+// OrderByUsing adds columns of ORDER BY USING op in DELETE.
+func (db *DeleteBuilder) OrderByUsing(op string, col ...string) *DeleteBuilder {
+	db.orderBy.add("USING "+op, col...)
+	return db
+}
+
+// Asc sets order of columns added by the latest OrderBy* call to ASC.
 func (db *DeleteBuilder) Asc() *DeleteBuilder {
-	db.order = "ASC"
+	db.orderBy.setOrder("ASC")
 	return db
 }
 
-// Desc sets order of ORDER BY to DESC.
+// Desc sets order of columns added by the latest OrderBy* call to DESC.
 func (db *DeleteBuilder) Desc() *DeleteBuilder {
-	db.order = "DESC"
+	db.orderBy.setOrder("DESC")
+	return db
+}
+
+// NullsFirst sorts NULLs first in columns added by the latest OrderBy* call.
+func (db *DeleteBuilder) NullsFirst() *DeleteBuilder {
+	db.orderBy.setNulls("FIRST")
+	return db
+}
+
+// NullsLast sorts NULLs last in columns added by the latest OrderBy* call.
+func (db *DeleteBuilder) NullsLast() *DeleteBuilder {
+	db.orderBy.setNulls("LAST")
 	return db
 }
 
@@ -82,39 +160,114 @@ func (db *DeleteBuilder) String() string {
 	return s
 }
 
+// InArrayThreshold sets the number of values above which `In` and `NotIn` of the builder
+// send all values as one array parameter. See `Args#SetInArrayThreshold` for details.
+func (db *DeleteBuilder) InArrayThreshold(threshold int) *DeleteBuilder {
+	db.args.SetInArrayThreshold(threshold)
+	return db
+}
+
+// QuoteIdents quotes the table and every name set in USING, RETURNING, ORDER BY and KeyCols,
+// e.g. "demo.user" is built as `"demo"."user"`.
+// Names like "*" or names already quoted by `Ident` are kept as they are.
+// As every name is quoted as a whole, aliases like "demo.user AS u" cannot be used in this mode.
+// Expressions in WHERE are not quoted.
+func (db *DeleteBuilder) QuoteIdents() *DeleteBuilder {
+	db.quoteIdents = true
+	return db
+}
+
+// Clone returns a deep copy of db.
+// The copy can be modified and built independently.
+func (db *DeleteBuilder) Clone() *DeleteBuilder {
+	clone := *db
+	clone.args = db.args.Clone()
+	clone.Cond.Args = clone.args
+	clone.returning = cloneStrings(db.returning)
+	clone.usingTables = cloneStrings(db.usingTables)
+	clone.whereExprs = cloneStrings(db.whereExprs)
+	clone.orderBy = db.orderBy.clone()
+	clone.keyCols = cloneStrings(db.keyCols)
+	return &clone
+}
+
+func (db *DeleteBuilder) cloneBuilder() Builder {
+	return db.Clone()
+}
+
 // Build returns compiled DELETE string and args.
 // They can be used in `DB#Query` of package `database/sql` directly.
+//
+// PostgreSQL doesn't support ORDER BY and LIMIT in DELETE.
+// If any of them is set, rows to delete are selected by a subquery like
+//
+//	DELETE FROM t WHERE ctid IN (SELECT ctid FROM t WHERE ... ORDER BY ... LIMIT n FOR UPDATE)
+//
+// Use `KeyCols` to match rows by other columns, e.g. the primary key.
 func (db *DeleteBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
-	buf := &strings.Builder{}
-	buf.WriteString("DELETE FROM ")
-	buf.WriteString(db.table)
+	return db.args.Compile(db.format(), initialArg...)
+}
+
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (db *DeleteBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = db.args.CompileE(db.format(), initialArg...)
+	return sql, args, errors.Join(db.Validate(), err)
+}
+
+// Validate reports a DELETE without table,
+// without WHERE if `RequireWhere` is set, or with KeyCols but no ORDER BY or LIMIT.
+func (db *DeleteBuilder) Validate() error {
+	var errs []error
+
+	if db.table == "" {
+		errs = append(errs, fmt.Errorf("%w in DELETE", ErrMissingTable))
+	}
+
+	if db.requireWhere && len(db.whereExprs) == 0 {
+		errs = append(errs, fmt.Errorf("%w in DELETE", ErrMissingWhere))
+	}
+
+	if len(db.keyCols) > 0 && db.orderBy.empty() && db.limit < 0 {
+		errs = append(errs, fmt.Errorf("%w in DELETE: KeyCols requires ORDER BY or LIMIT", ErrInvalidClause))
+	}
 
-	if len(db.whereExprs) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(db.whereExprs, " AND "))
+	return errors.Join(errs...)
+}
 
+// format returns DELETE in the format of `Args#Compile`.
+func (db *DeleteBuilder) format() string {
+	buf := &strings.Builder{}
+
+	if db.cteVar != "" {
+		buf.WriteString(db.cteVar)
+		buf.WriteRune(' ')
 	}
 
-	if len(db.orderByCols) > 0 {
-		buf.WriteString(" ORDER BY ")
-		buf.WriteString(strings.Join(db.orderByCols, ", "))
+	table := quoteIdentName(db.quoteIdents, db.table)
+	usingTables := quoteIdentNames(db.quoteIdents, db.usingTables)
 
-		if db.order != "" {
-			buf.WriteRune(' ')
-			buf.WriteString(db.order)
-		}
+	buf.WriteString("DELETE FROM ")
+	buf.WriteString(table)
 
+	if len(db.usingTables) > 0 {
+		buf.WriteString(" USING ")
+		buf.WriteString(strings.Join(usingTables, ", "))
 	}
 
-	if db.limit >= 0 {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.Itoa(db.limit))
+	lw := &limitedWhere{
+		table:      table,
+		joinTables: usingTables,
+		whereExprs: db.whereExprs,
+		keyCols:    quoteIdentNames(db.quoteIdents, db.keyCols),
+		orderBy:    db.orderBy.quoted(db.quoteIdents),
+		limit:      db.limit,
 	}
+	lw.writeTo(buf)
 
 	if len(db.returning) != 0 {
 		buf.WriteString(" RETURNING ")
-		buf.WriteString(strings.Join(db.returning, ", "))
+		buf.WriteString(strings.Join(quoteIdentNames(db.quoteIdents, db.returning), ", "))
 	}
 
-	return db.args.Compile(buf.String(), initialArg...)
+	return buf.String()
 }
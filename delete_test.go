@@ -14,7 +14,7 @@ func TestDelete1(t *testing.T) {
 		Limit(10).
 		Build()
 
-	assert.Equal(t, "DELETE FROM demo.user WHERE status = 1 LIMIT 10", result)
+	assert.Equal(t, "DELETE FROM demo.user WHERE ctid IN (SELECT ctid FROM demo.user WHERE status = 1 LIMIT 10 FOR UPDATE)", result)
 	assert.Empty(t, args)
 }
 
@@ -36,3 +36,104 @@ func TestDelete2(t *testing.T) {
 	assert.Equal(t, "DELETE FROM demo.user WHERE id > $1 AND name LIKE $2 AND (id_card IS NULL OR status IN ($3, $4, $5)) AND modified_at > created_at + $6", result)
 	assert.Equal(t, []interface{}{1234, "%Du", 1, 2, 5, 86400}, args)
 }
+
+func TestDeleteReturning(t *testing.T) {
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.session")
+	db.Where(db.LT("expires_at", "2023-01-01"))
+	db.Returning("*")
+
+	result, args := db.Build()
+
+	assert.Equal(t, "DELETE FROM demo.session WHERE expires_at < $1 RETURNING *", result)
+	assert.Equal(t, []interface{}{"2023-01-01"}, args)
+
+	db = NewDeleteBuilder()
+	db.DeleteFrom("demo.session")
+	db.Where(db.EQ("user_id", 7))
+	db.Returning("id", "coalesce(note, "+db.Var("n/a")+")")
+
+	result, args = db.Build()
+
+	assert.Equal(t, "DELETE FROM demo.session WHERE user_id = $1 RETURNING id, coalesce(note, $2)", result)
+	assert.Equal(t, []interface{}{7, "n/a"}, args)
+}
+
+func TestDeleteUsing(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id").From("demo.user").Where(sb.EQ("status", 0))
+
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.session s")
+	db.Using("demo.device d", db.BuilderAs(sb, "u"))
+	db.Where("s.device_id = d.id", "s.user_id = u.id", db.EQ("d.kind", "mobile"))
+
+	result, args := db.Build()
+
+	assert.Equal(t, "DELETE FROM demo.session s USING demo.device d, (SELECT id FROM demo.user WHERE status = $1) AS u WHERE s.device_id = d.id AND s.user_id = u.id AND d.kind = $2", result)
+	assert.Equal(t, []interface{}{0, "mobile"}, args)
+}
+
+func TestDeleteOrderByLimit(t *testing.T) {
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.event")
+	db.Where(db.LT("created_at", "2023-01-01"))
+	db.OrderBy("created_at").Asc()
+	db.Limit(1000)
+	db.KeyCols("id")
+
+	result, args := db.Build()
+
+	assert.Equal(t, "DELETE FROM demo.event WHERE id IN (SELECT id FROM demo.event WHERE created_at < $1 ORDER BY created_at ASC LIMIT 1000 FOR UPDATE)", result)
+	assert.Equal(t, []interface{}{"2023-01-01"}, args)
+}
+
+func TestDeleteUsingLimit(t *testing.T) {
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.session AS s")
+	db.Using("demo.user u")
+	db.Where("s.user_id = u.id", db.EQ("u.status", 0))
+	db.Limit(10)
+	db.Returning("s.id")
+
+	result, args := db.Build()
+
+	assert.Equal(t, "DELETE FROM demo.session AS s USING demo.user u WHERE s.user_id = u.id AND u.status = $1 AND s.ctid IN (SELECT s.ctid FROM demo.session AS s, demo.user u WHERE s.user_id = u.id AND u.status = $1 LIMIT 10 FOR UPDATE OF s) RETURNING s.id", result)
+	assert.Equal(t, []interface{}{0}, args)
+}
+
+func TestDeleteOrderBy(t *testing.T) {
+	result, _ := DeleteFrom("demo.event").
+		OrderByAsc("priority").
+		OrderBy("created_at").Desc().NullsFirst().
+		Limit(100).
+		Build()
+
+	assert.Equal(t, "DELETE FROM demo.event WHERE ctid IN (SELECT ctid FROM demo.event ORDER BY priority ASC, created_at DESC NULLS FIRST LIMIT 100 FOR UPDATE)", result)
+}
+
+func TestDeleteQuoteIdents(t *testing.T) {
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.user").Using("demo.session").Where(db.EQ("id", 1)).Returning("*").QuoteIdents()
+
+	result, args := db.Build()
+
+	assert.Equal(t, `DELETE FROM "demo"."user" USING "demo"."session" WHERE id = $1 RETURNING *`, result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestDeleteClone(t *testing.T) {
+	base := NewDeleteBuilder()
+	base.DeleteFrom("demo.user").Where(base.EQ("status", 0))
+
+	clone := base.Clone()
+	clone.Where(clone.LT("id", 100)).Returning("id")
+
+	result, args := base.Build()
+	assert.Equal(t, "DELETE FROM demo.user WHERE status = $1", result)
+	assert.Equal(t, []interface{}{0}, args)
+
+	result, args = clone.Build()
+	assert.Equal(t, "DELETE FROM demo.user WHERE status = $1 AND id < $2 RETURNING id", result)
+	assert.Equal(t, []interface{}{0, 100}, args)
+}
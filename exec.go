@@ -0,0 +1,231 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Runner runs SQL with args.
+// It's satisfied by `*sql.DB`, `*sql.Tx` and `*sql.Conn` of package `database/sql`.
+type Runner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ExecContext builds builder and executes it with r.
+// The builder is not executed if `BuildE` reports any error.
+func ExecContext(ctx context.Context, r Runner, builder Builder) (sql.Result, error) {
+	query, args, err := BuildE(builder)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ExecContext(ctx, query, args...)
+}
+
+// QueryContext builds builder and queries rows with r.
+// The builder is not executed if `BuildE` reports any error.
+func QueryContext(ctx context.Context, r Runner, builder Builder) (*sql.Rows, error) {
+	query, args, err := BuildE(builder)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext builds builder, queries a row with r and scans the row into dest.
+// It returns `sql.ErrNoRows` if there is no row.
+// The builder is not executed if `BuildE` reports any error.
+func QueryRowContext(ctx context.Context, r Runner, builder Builder, dest ...interface{}) error {
+	query, args, err := BuildE(builder)
+
+	if err != nil {
+		return err
+	}
+
+	return r.QueryRowContext(ctx, query, args...).Scan(dest...)
+}
+
+// ScanAll scans all rows into dest and closes rows.
+//
+// The dest must be a pointer to a slice. Rows are appended to the slice.
+// If the element type is a struct or a pointer to struct, columns are mapped to fields by `Struct`
+// and columns not mapped are discarded.
+// Otherwise, including time.Time and types implementing `sql.Scanner`,
+// rows must have exactly one column which is scanned into the element.
+func ScanAll(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("pgsql: dest must be a pointer to slice, got %T", dest)
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	cols, err := rows.Columns()
+
+	if err != nil {
+		return err
+	}
+
+	var s *Struct
+
+	if isScanStruct(elemType) {
+		s = structOf(reflect.New(elemType).Interface())
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+
+		if err := scanRow(rows, s, cols, elem.Interface()); err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	v.Elem().Set(slice)
+	return rows.Close()
+}
+
+// ScanOne scans the first row into dest and closes rows.
+// It returns `sql.ErrNoRows` if there is no row.
+//
+// The dest must be a pointer. Columns are mapped to fields in the same way as `ScanAll`.
+func ScanOne(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("pgsql: dest must be a pointer, got %T", dest)
+	}
+
+	cols, err := rows.Columns()
+
+	if err != nil {
+		return err
+	}
+
+	var s *Struct
+
+	if isScanStruct(v.Elem().Type()) {
+		s = structOf(dest)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return sql.ErrNoRows
+	}
+
+	if err := scanRow(rows, s, cols, dest); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+var typeOfScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// isScanStruct reports whether values of t are scanned field by field.
+func isScanStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != typeOfTime && !reflect.PtrTo(t).Implements(typeOfScanner)
+}
+
+func scanRow(rows *sql.Rows, s *Struct, cols []string, dest interface{}) error {
+	if s == nil {
+		return rows.Scan(dest)
+	}
+
+	return rows.Scan(s.AddrWithCols(cols, dest)...)
+}
+
+// QueryContext builds SELECT and queries rows with r.
+func (sb *SelectBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return QueryContext(ctx, r, sb)
+}
+
+// QueryRowContext builds SELECT, queries a row with r and scans the row into dest.
+func (sb *SelectBuilder) QueryRowContext(ctx context.Context, r Runner, dest ...interface{}) error {
+	return QueryRowContext(ctx, r, sb, dest...)
+}
+
+// QueryContext builds set operations and queries rows with r.
+func (ub *UnionBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return QueryContext(ctx, r, ub)
+}
+
+// QueryRowContext builds set operations, queries a row with r and scans the row into dest.
+func (ub *UnionBuilder) QueryRowContext(ctx context.Context, r Runner, dest ...interface{}) error {
+	return QueryRowContext(ctx, r, ub, dest...)
+}
+
+// ExecContext builds INSERT and executes it with r.
+func (ib *InsertBuilder) ExecContext(ctx context.Context, r Runner) (sql.Result, error) {
+	return ExecContext(ctx, r, ib)
+}
+
+// QueryContext builds INSERT and queries rows in RETURNING with r.
+func (ib *InsertBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return QueryContext(ctx, r, ib)
+}
+
+// QueryRowContext builds INSERT, queries a row in RETURNING with r and scans the row into dest.
+func (ib *InsertBuilder) QueryRowContext(ctx context.Context, r Runner, dest ...interface{}) error {
+	return QueryRowContext(ctx, r, ib, dest...)
+}
+
+// ExecContext builds UPDATE and executes it with r.
+func (ub *UpdateBuilder) ExecContext(ctx context.Context, r Runner) (sql.Result, error) {
+	return ExecContext(ctx, r, ub)
+}
+
+// QueryContext builds UPDATE and queries rows in RETURNING with r.
+func (ub *UpdateBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return QueryContext(ctx, r, ub)
+}
+
+// QueryRowContext builds UPDATE, queries a row in RETURNING with r and scans the row into dest.
+func (ub *UpdateBuilder) QueryRowContext(ctx context.Context, r Runner, dest ...interface{}) error {
+	return QueryRowContext(ctx, r, ub, dest...)
+}
+
+// ExecContext builds DELETE and executes it with r.
+func (db *DeleteBuilder) ExecContext(ctx context.Context, r Runner) (sql.Result, error) {
+	return ExecContext(ctx, r, db)
+}
+
+// QueryContext builds DELETE and queries rows in RETURNING with r.
+func (db *DeleteBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return QueryContext(ctx, r, db)
+}
+
+// QueryRowContext builds DELETE, queries a row in RETURNING with r and scans the row into dest.
+func (db *DeleteBuilder) QueryRowContext(ctx context.Context, r Runner, dest ...interface{}) error {
+	return QueryRowContext(ctx, r, db, dest...)
+}
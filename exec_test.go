@@ -0,0 +1,185 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB is an in-memory database/sql driver recording queries and returning fixed rows.
+type fakeDB struct {
+	queries []string
+	args    [][]interface{}
+	cols    []string
+	rows    [][]driver.Value
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *fakeConn) Commit() error                       { return nil }
+func (c *fakeConn) Rollback() error                     { return nil }
+
+func (c *fakeConn) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, 0, len(args))
+
+	for _, a := range args {
+		values = append(values, a.Value)
+	}
+
+	c.db.queries = append(c.db.queries, query)
+	c.db.args = append(c.db.args, values)
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.record(query, args)
+	return driver.RowsAffected(len(c.db.rows)), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.record(query, args)
+	return &fakeRows{cols: c.db.cols, rows: c.db.rows}, nil
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type execUser struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user").Set(ub.Assign("status", 1)).Where(ub.EQ("id", 2))
+	result, err := ub.ExecContext(ctx, db)
+
+	if assert.NoError(t, err) {
+		n, _ := result.RowsAffected()
+		assert.Equal(t, int64(0), n)
+	}
+
+	assert.Equal(t, []string{"UPDATE demo.user SET status = $1 WHERE id = $2"}, fake.queries)
+	assert.Equal(t, [][]interface{}{{int64(1), int64(2)}}, fake.args)
+
+	_, err = DeleteFrom("demo.user").RequireWhere().ExecContext(ctx, db)
+	assert.ErrorIs(t, err, ErrMissingWhere)
+	assert.Len(t, fake.queries, 1)
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake := &fakeDB{
+		cols: []string{"id", "name", "created_at", "extra"},
+		rows: [][]driver.Value{
+			{int64(1), "a", now, "x"},
+			{int64(2), "b", now, "y"},
+		},
+	}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.user").Where(sb.GT("id", 0))
+
+	rows, err := sb.QueryContext(ctx, db)
+	assert.NoError(t, err)
+
+	var users []execUser
+	assert.NoError(t, ScanAll(rows, &users))
+	assert.Equal(t, []execUser{{1, "a", now}, {2, "b", now}}, users)
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id > $1", fake.queries[0])
+
+	rows, err = sb.QueryContext(ctx, db)
+	assert.NoError(t, err)
+
+	var ptrs []*execUser
+	assert.NoError(t, ScanAll(rows, &ptrs))
+	assert.Equal(t, []*execUser{{1, "a", now}, {2, "b", now}}, ptrs)
+
+	rows, err = sb.QueryContext(ctx, db)
+	assert.NoError(t, err)
+
+	var user execUser
+	assert.NoError(t, ScanOne(rows, &user))
+	assert.Equal(t, execUser{1, "a", now}, user)
+
+	var id int64
+	var name string
+	assert.NoError(t, sb.QueryRowContext(ctx, db, &id, &name, new(time.Time), new(string)))
+	assert.Equal(t, int64(1), id)
+	assert.Equal(t, "a", name)
+
+	fake.cols = []string{"created_at"}
+	fake.rows = [][]driver.Value{{now}}
+	rows, err = Select("created_at").From("demo.user").QueryContext(ctx, db)
+	assert.NoError(t, err)
+
+	var times []time.Time
+	assert.NoError(t, ScanAll(rows, &times))
+	assert.Equal(t, []time.Time{now}, times)
+
+	fake.rows = nil
+	rows, err = Select("created_at").From("demo.user").QueryContext(ctx, db)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, ScanOne(rows, &now), sql.ErrNoRows)
+
+	assert.ErrorIs(t, Select("*").From("demo.user").QueryRowContext(ctx, db, &id), sql.ErrNoRows)
+
+	_, err = NewSelectBuilder().From("demo.user").QueryContext(ctx, db)
+	assert.ErrorIs(t, err, ErrMissingColumns)
+}
+
+func TestQueryTx(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeDB{
+		cols: []string{"id"},
+		rows: [][]driver.Value{{int64(3)}},
+	}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	var id int64
+	assert.NoError(t, InsertInto("demo.user").Cols("name").Values("a").Returning("id").QueryRowContext(ctx, tx, &id))
+	assert.Equal(t, int64(3), id)
+	assert.Equal(t, []string{"INSERT INTO demo.user (name) VALUES ($1) RETURNING id"}, fake.queries)
+}
@@ -0,0 +1,304 @@
+package pgsql
+
+import (
+	"strings"
+)
+
+// Expr is a condition which is built lazily.
+//
+// Unlike methods of Cond, functions creating Expr don't add args to any builder.
+// Args are added only when the Expr is built within a builder by `WhereExpr`, `HavingExpr`, etc.,
+// so that an Expr can be created before knowing the builder and be reused in many builders.
+//
+//	active := And(EQ("status", 1), IsNull("deleted_at"))
+//	Select("*").From("demo.user").WhereExpr(active)
+//	DeleteFrom("demo.user").WhereExpr(Not(active))
+type Expr interface {
+	Builder
+
+	// expr prevents other builders from being used as an Expr accidentally.
+	expr()
+}
+
+// condExpr is an Expr with a format in the syntax of `Args#Compile`.
+type condExpr struct {
+	format string
+	args   []interface{}
+}
+
+// NewExpr creates an Expr from a format string with args.
+// The format string uses the same syntax as `Build`.
+//
+//	NewExpr("age(birthday) > $?", "18 years")
+func NewExpr(format string, arg ...interface{}) Expr {
+	return &condExpr{
+		format: format,
+		args:   arg,
+	}
+}
+
+func (e *condExpr) expr() {}
+
+func (e *condExpr) compileArgs() *Args {
+	args := &Args{}
+
+	for _, a := range e.args {
+		args.Add(a)
+	}
+
+	return args
+}
+
+// Build returns compiled expression and args.
+func (e *condExpr) Build(initialArg ...interface{}) (sql string, args []interface{}) {
+	return e.compileArgs().Compile(e.format, initialArg...)
+}
+
+// BuildE is like Build but also reports errors found in compiling args.
+func (e *condExpr) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	return e.compileArgs().CompileE(e.format, initialArg...)
+}
+
+// Op represents "field op value".
+func Op(field, op string, value interface{}) Expr {
+	return NewExpr(Escape(field)+" "+Escape(op)+" $0", value)
+}
+
+// EQ represents "field = value".
+func EQ(field string, value interface{}) Expr {
+	return Op(field, "=", value)
+}
+
+// NE represents "field <> value".
+func NE(field string, value interface{}) Expr {
+	return Op(field, "<>", value)
+}
+
+// GT represents "field > value".
+func GT(field string, value interface{}) Expr {
+	return Op(field, ">", value)
+}
+
+// GE represents "field >= value".
+func GE(field string, value interface{}) Expr {
+	return Op(field, ">=", value)
+}
+
+// LT represents "field < value".
+func LT(field string, value interface{}) Expr {
+	return Op(field, "<", value)
+}
+
+// LE represents "field <= value".
+func LE(field string, value interface{}) Expr {
+	return Op(field, "<=", value)
+}
+
+// Like represents "field LIKE value".
+func Like(field string, value interface{}) Expr {
+	return Op(field, "LIKE", value)
+}
+
+// NotLike represents "field NOT LIKE value".
+func NotLike(field string, value interface{}) Expr {
+	return Op(field, "NOT LIKE", value)
+}
+
+// ILike represents "field ILIKE value".
+func ILike(field string, value interface{}) Expr {
+	return Op(field, "ILIKE", value)
+}
+
+// In represents "field IN (value...)".
+// Use `InArray` to send values in one array parameter.
+// It's "FALSE" if there is no value.
+func In(field string, value ...interface{}) Expr {
+	if len(value) == 0 {
+		return NewExpr("FALSE")
+	}
+
+	return listExpr(field, " IN (", value)
+}
+
+// NotIn represents "field NOT IN (value...)".
+// Use `NotInArray` to send values in one array parameter.
+// It's "TRUE" if there is no value.
+func NotIn(field string, value ...interface{}) Expr {
+	if len(value) == 0 {
+		return NewExpr("TRUE")
+	}
+
+	return listExpr(field, " NOT IN (", value)
+}
+
+// InArray represents "field = ANY($1)" with array as one parameter.
+// See `Cond#InArray` for details.
+func InArray(field string, array interface{}) Expr {
+	return AnyArray(field, "=", array)
+}
+
+// NotInArray represents "field <> ALL($1)" with array as one parameter.
+func NotInArray(field string, array interface{}) Expr {
+	return NewExpr(Escape(field)+" <> ALL($0)", array)
+}
+
+// AnyArray represents "field op ANY($1)" with array as one parameter.
+func AnyArray(field, op string, array interface{}) Expr {
+	return NewExpr(Escape(field)+" "+Escape(op)+" ANY($0)", array)
+}
+
+func listExpr(field, op string, value []interface{}) Expr {
+	buf := &strings.Builder{}
+	buf.WriteString(Escape(field))
+	buf.WriteString(op)
+
+	for i := range value {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString("$?")
+	}
+
+	buf.WriteString(")")
+	return NewExpr(buf.String(), value...)
+}
+
+// IsNull represents "field IS NULL".
+func IsNull(field string) Expr {
+	return NewExpr(Escape(field) + " IS NULL")
+}
+
+// IsNotNull represents "field IS NOT NULL".
+func IsNotNull(field string) Expr {
+	return NewExpr(Escape(field) + " IS NOT NULL")
+}
+
+// Between represents "field BETWEEN lower AND upper".
+func Between(field string, lower, upper interface{}) Expr {
+	return NewExpr(Escape(field)+" BETWEEN $0 AND $1", lower, upper)
+}
+
+// NotBetween represents "field NOT BETWEEN lower AND upper".
+func NotBetween(field string, lower, upper interface{}) Expr {
+	return NewExpr(Escape(field)+" NOT BETWEEN $0 AND $1", lower, upper)
+}
+
+// Exists represents "EXISTS (subquery)".
+func Exists(subquery Builder) Expr {
+	return NewExpr("EXISTS ($0)", subquery)
+}
+
+// NotExists represents "NOT EXISTS (subquery)".
+func NotExists(subquery Builder) Expr {
+	return NewExpr("NOT EXISTS ($0)", subquery)
+}
+
+// And represents AND logic like "(expr1 AND expr2 AND expr3)".
+// Nil exprs are skipped. It's "TRUE" if there is no expr.
+func And(expr ...Expr) Expr {
+	return logicExpr(" AND ", "TRUE", expr)
+}
+
+// Or represents OR logic like "(expr1 OR expr2 OR expr3)".
+// Nil exprs are skipped. It's "FALSE" if there is no expr.
+func Or(expr ...Expr) Expr {
+	return logicExpr(" OR ", "FALSE", expr)
+}
+
+func logicExpr(op, empty string, expr []Expr) Expr {
+	args := make([]interface{}, 0, len(expr))
+
+	for _, e := range expr {
+		if e != nil {
+			args = append(args, e)
+		}
+	}
+
+	if len(args) == 0 {
+		return NewExpr(empty)
+	}
+
+	return NewExpr("($?"+strings.Repeat(op+"$?", len(args)-1)+")", args...)
+}
+
+// Not represents "NOT (expr)".
+// Like `And` and `Or`, a nil expr is skipped, so it's "TRUE" if expr is nil.
+func Not(expr Expr) Expr {
+	if expr == nil {
+		return NewExpr("TRUE")
+	}
+
+	return NewExpr("NOT ($0)", expr)
+}
+
+// Assign represents "field = value" in SET of UPDATE or DO UPDATE of INSERT.
+func Assign(field string, value interface{}) Expr {
+	return NewExpr(Escape(field)+" = $0", value)
+}
+
+// Excluded represents "col = EXCLUDED.col" in DO UPDATE of INSERT.
+func Excluded(col string) Expr {
+	col = Escape(col)
+	return NewExpr(col + " = EXCLUDED." + col)
+}
+
+// exprVars returns placeholders of all non-nil exprs in args.
+func exprVars(args *Args, expr []Expr) []string {
+	vars := make([]string, 0, len(expr))
+
+	for _, e := range expr {
+		if e != nil {
+			vars = append(vars, args.Add(e))
+		}
+	}
+
+	return vars
+}
+
+// WhereExpr adds exprs to WHERE in SELECT.
+func (sb *SelectBuilder) WhereExpr(andExpr ...Expr) *SelectBuilder {
+	return sb.Where(exprVars(sb.args, andExpr)...)
+}
+
+// HavingExpr adds exprs to HAVING in SELECT.
+func (sb *SelectBuilder) HavingExpr(andExpr ...Expr) *SelectBuilder {
+	return sb.Having(exprVars(sb.args, andExpr)...)
+}
+
+// JoinExpr sets exprs of JOIN with an option in SELECT.
+// It's the same as `JoinWithOption` except that ON conditions are exprs.
+func (sb *SelectBuilder) JoinExpr(option JoinOption, table string, onExpr ...Expr) *SelectBuilder {
+	return sb.JoinWithOption(option, table, exprVars(sb.args, onExpr)...)
+}
+
+// WhereExpr adds exprs to WHERE in UPDATE.
+func (ub *UpdateBuilder) WhereExpr(andExpr ...Expr) *UpdateBuilder {
+	return ub.Where(exprVars(ub.args, andExpr)...)
+}
+
+// SetExpr sets assignments in SET in UPDATE, e.g. `SetExpr(Assign("status", 1))`.
+func (ub *UpdateBuilder) SetExpr(assignment ...Expr) *UpdateBuilder {
+	return ub.Set(exprVars(ub.args, assignment)...)
+}
+
+// WhereExpr adds exprs to WHERE in DELETE.
+func (db *DeleteBuilder) WhereExpr(andExpr ...Expr) *DeleteBuilder {
+	return db.Where(exprVars(db.args, andExpr)...)
+}
+
+// OnConflictWhereExpr adds exprs to the WHERE of the conflict target in INSERT.
+func (ib *InsertBuilder) OnConflictWhereExpr(andExpr ...Expr) *InsertBuilder {
+	return ib.OnConflictWhere(exprVars(ib.args, andExpr)...)
+}
+
+// DoUpdateExpr sets the conflict action to DO UPDATE SET with assignments in INSERT,
+// e.g. `DoUpdateExpr(Excluded("name"), Assign("version", 2))`.
+func (ib *InsertBuilder) DoUpdateExpr(assignment ...Expr) *InsertBuilder {
+	return ib.DoUpdate(exprVars(ib.args, assignment)...)
+}
+
+// DoUpdateWhereExpr adds exprs to the WHERE of ON CONFLICT DO UPDATE in INSERT.
+func (ib *InsertBuilder) DoUpdateWhereExpr(andExpr ...Expr) *InsertBuilder {
+	return ib.DoUpdateWhere(exprVars(ib.args, andExpr)...)
+}
@@ -0,0 +1,106 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpr(t *testing.T) {
+	active := And(EQ("status", 1), IsNull("deleted_at"))
+	filter := Or(active, In("role", "admin", "owner"), Not(Between("age", 18, 65)))
+
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.user").Where(sb.GT("id", 100)).WhereExpr(filter, nil, Like("name", "a%"))
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id > $1 AND ((status = $2 AND deleted_at IS NULL) OR role IN ($3, $4) OR NOT (age BETWEEN $5 AND $6)) AND name LIKE $7", result)
+	assert.Equal(t, []interface{}{100, 1, "admin", "owner", 18, 65, "a%"}, args)
+
+	db := NewDeleteBuilder()
+	db.DeleteFrom("demo.user").Where(db.EQ("kind", "guest")).WhereExpr(Not(active))
+
+	result, args = db.Build()
+	assert.Equal(t, "DELETE FROM demo.user WHERE kind = $1 AND NOT ((status = $2 AND deleted_at IS NULL))", result)
+	assert.Equal(t, []interface{}{"guest", 1}, args)
+
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user").Set(ub.Assign("visited", 0)).WhereExpr(active, GE("visited", 10))
+
+	result, args = ub.Build()
+	assert.Equal(t, "UPDATE demo.user SET visited = $1 WHERE (status = $2 AND deleted_at IS NULL) AND visited >= $3", result)
+	assert.Equal(t, []interface{}{0, 1, 10}, args)
+}
+
+func TestExprJoinHaving(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("u.id", "count(*)").
+		From("demo.user u").
+		JoinExpr(LeftJoin, "demo.order o", NewExpr("o.user_id = u.id"), GT("o.total", 100)).
+		WhereExpr(Exists(Select("1").From("demo.admin a").Where("a.user_id = u.id")), NE("u.status", 0)).
+		GroupBy("u.id").
+		HavingExpr(Op("count(*)", ">=", 3))
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT u.id, count(*) FROM demo.user u LEFT JOIN demo.order o ON o.user_id = u.id AND o.total > $1 WHERE EXISTS (SELECT 1 FROM demo.admin a WHERE a.user_id = u.id) AND u.status <> $2 GROUP BY u.id HAVING count(*) >= $3", result)
+	assert.Equal(t, []interface{}{100, 0, 3}, args)
+}
+
+func TestExprMisc(t *testing.T) {
+	cases := []struct {
+		expr     Expr
+		expected string
+		args     []interface{}
+	}{
+		{LT("a", 1), "a < $1", []interface{}{1}},
+		{LE("a", 1), "a <= $1", []interface{}{1}},
+		{NotLike("a", "x"), "a NOT LIKE $1", []interface{}{"x"}},
+		{ILike("a", "x"), "a ILIKE $1", []interface{}{"x"}},
+		{NotIn("a", 1, 2), "a NOT IN ($1, $2)", []interface{}{1, 2}},
+		{In("a"), "FALSE", nil},
+		{NotIn("a"), "TRUE", nil},
+		{IsNotNull("a"), "a IS NOT NULL", nil},
+		{NotBetween("a", 1, 2), "a NOT BETWEEN $1 AND $2", []interface{}{1, 2}},
+		{NotExists(Build("SELECT $?", 1)), "NOT EXISTS (SELECT $1)", []interface{}{1}},
+		{And(), "TRUE", nil},
+		{Or(nil), "FALSE", nil},
+		{Not(nil), "TRUE", nil},
+		{EQ("price$", 1), "price$ = $1", []interface{}{1}},
+		{NewExpr("age(birthday) > $?", "18 years"), "age(birthday) > $1", []interface{}{"18 years"}},
+	}
+
+	for _, c := range cases {
+		result, args := c.expr.Build()
+		assert.Equal(t, c.expected, result)
+		assert.Equal(t, c.args, args)
+	}
+
+	_, _, err := BuildE(NewExpr("a = $1", 1))
+	assert.ErrorIs(t, err, ErrArgOutOfRange)
+}
+
+func TestExprInsert(t *testing.T) {
+	ib := InsertInto("demo.user").Cols("email", "name").Values("a@b.c", "a")
+	ib.OnConflict("email").OnConflictWhereExpr(IsNull("deleted_at"))
+	ib.DoUpdate(ib.Set("name")).DoUpdateWhereExpr(NE("demo.user.name", "root"))
+
+	result, args := ib.Build()
+	assert.Equal(t, "INSERT INTO demo.user (email, name) VALUES ($1, $2) ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET name = EXCLUDED.name WHERE demo.user.name <> $3", result)
+	assert.Equal(t, []interface{}{"a@b.c", "a", "root"}, args)
+
+	ib = InsertInto("demo.user").Cols("email", "name").Values("a@b.c", "a")
+	ib.OnConflict("email").DoUpdateExpr(Excluded("name"), Assign("version", 2), nil)
+
+	result, args = ib.Build()
+	assert.Equal(t, "INSERT INTO demo.user (email, name) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, version = $3", result)
+	assert.Equal(t, []interface{}{"a@b.c", "a", 2}, args)
+}
+
+func TestExprUpdateSet(t *testing.T) {
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user").SetExpr(Assign("status", 1), Assign("note", nil)).WhereExpr(In("id", 1, 2))
+
+	result, args := ub.Build()
+	assert.Equal(t, "UPDATE demo.user SET status = $1, note = $2 WHERE id IN ($3, $4)", result)
+	assert.Equal(t, []interface{}{1, nil, 1, 2}, args)
+}
@@ -1,6 +1,7 @@
 package pgsql
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -13,6 +14,9 @@ func NewInsertBuilder() *InsertBuilder {
 func newInsertBuilder() *InsertBuilder {
 	args := &Args{}
 	return &InsertBuilder{
+		Cond: Cond{
+			Args: args,
+		},
 		verb: "INSERT",
 		args: args,
 	}
@@ -20,7 +24,10 @@ func newInsertBuilder() *InsertBuilder {
 
 // InsertBuilder is a builder to build INSERT.
 type InsertBuilder struct {
+	Cond
+
 	args        *Args
+	cteVar      string
 	verb        string
 	table       string
 	returning   []string
@@ -28,6 +35,16 @@ type InsertBuilder struct {
 	assignments []string
 	cols        []string
 	values      [][]string
+
+	onConstraint       string
+	conflictWhereExprs []string
+	updateWhereExprs   []string
+	doNothing          bool
+	doUpdate           bool
+
+	selectVar     string
+	defaultValues bool
+	quoteIdents   bool
 }
 
 // InsertInto sets table name in INSERT.
@@ -41,18 +58,27 @@ func (ib *InsertBuilder) InsertInto(table string) *InsertBuilder {
 	return ib
 }
 
+// With sets the WITH clause of INSERT.
+func (ib *InsertBuilder) With(cte *CTEBuilder) *InsertBuilder {
+	ib.cteVar = ib.Var(cte)
+	return ib
+}
+
 // Cols sets columns in INSERT.
 func (ib *InsertBuilder) Cols(col ...string) *InsertBuilder {
 	ib.cols = col
 	return ib
 }
 
+// Returning sets columns or expressions of RETURNING in INSERT.
+// Use "*" to return all columns.
 func (ib *InsertBuilder) Returning(col ...string) *InsertBuilder {
 	ib.returning = col
 	return ib
 }
 
 // Values adds a list of values for a row in INSERT.
+// Use `Default()` as a value to insert the default value of a column.
 func (ib *InsertBuilder) Values(value ...interface{}) *InsertBuilder {
 	placeholders := make([]string, 0, len(value))
 
@@ -64,68 +90,279 @@ func (ib *InsertBuilder) Values(value ...interface{}) *InsertBuilder {
 	return ib
 }
 
-// Cols sets columns in INSERT.
+// Select sets a query providing rows in INSERT.
+// It builds an INSERT statement like
+//
+//	INSERT INTO table (col...) SELECT ...
+func (ib *InsertBuilder) Select(builder Builder) *InsertBuilder {
+	ib.selectVar = ib.Var(builder)
+	return ib
+}
+
+// DefaultValues inserts a single row with default values of all columns.
+// It builds an INSERT statement like
+//
+//	INSERT INTO table DEFAULT VALUES
+func (ib *InsertBuilder) DefaultValues() *InsertBuilder {
+	ib.defaultValues = true
+	return ib
+}
+
+// OnConflict sets the conflict target of ON CONFLICT in INSERT.
+// Each target is a column name or an index expression surrounded by parens,
+// e.g. "(lower(email))".
+// The conflict action must be set by `DoNothing` or `DoUpdate`.
 func (ib *InsertBuilder) OnConflict(col ...string) *InsertBuilder {
 	ib.onConflict = col
 	return ib
 }
 
-// Cols sets columns in INSERT.
+// OnConstraint sets the conflict target of ON CONFLICT to a constraint name.
+// It builds an ON CONFLICT expression like
+//
+//	ON CONFLICT ON CONSTRAINT name
+func (ib *InsertBuilder) OnConstraint(name string) *InsertBuilder {
+	ib.onConstraint = name
+	return ib
+}
+
+// OnConflictWhere sets expressions of WHERE in the conflict target
+// to infer a partial unique index.
+func (ib *InsertBuilder) OnConflictWhere(andExpr ...string) *InsertBuilder {
+	ib.conflictWhereExprs = append(ib.conflictWhereExprs, andExpr...)
+	return ib
+}
+
+// DoNothing sets the conflict action to DO NOTHING.
+func (ib *InsertBuilder) DoNothing() *InsertBuilder {
+	ib.doNothing = true
+	ib.doUpdate = false
+	return ib
+}
+
+// DoUpdate sets the conflict action to DO UPDATE SET with assignments.
+// PostgreSQL requires a conflict target set by `OnConflict` or `OnConstraint` for DO UPDATE.
 func (ib *InsertBuilder) DoUpdate(assignment ...string) *InsertBuilder {
+	ib.doNothing = false
+	ib.doUpdate = true
 	ib.assignments = assignment
 	return ib
 }
 
-// Assign represents SET "field = value" in UPDATE.
-func (ub *InsertBuilder) Set(col string) string {
+// DoUpdateWhere sets expressions of WHERE in DO UPDATE.
+// Only rows matching all expressions are updated.
+func (ib *InsertBuilder) DoUpdateWhere(andExpr ...string) *InsertBuilder {
+	ib.updateWhereExprs = append(ib.updateWhereExprs, andExpr...)
+	return ib
+}
+
+// Set represents SET "field = EXCLUDED.field" in DO UPDATE.
+func (ib *InsertBuilder) Set(col string) string {
 	return fmt.Sprintf("%s = EXCLUDED.%s", col, col)
 }
 
-// Assign represents SET "field = value" in UPDATE.
-func (ub *InsertBuilder) Assign(field string, value interface{}) string {
-	return fmt.Sprintf("%s = %s", field, ub.args.Add(value))
+// Assign represents SET "field = value" in DO UPDATE.
+func (ib *InsertBuilder) Assign(field string, value interface{}) string {
+	return fmt.Sprintf("%s = %s", field, ib.args.Add(value))
 }
 
-// BuildWithFlavor returns compiled INSERT string and args with flavor and initial args.
+// InArrayThreshold sets the number of values above which `In` and `NotIn` of the builder
+// send all values as one array parameter. See `Args#SetInArrayThreshold` for details.
+func (ib *InsertBuilder) InArrayThreshold(threshold int) *InsertBuilder {
+	ib.args.SetInArrayThreshold(threshold)
+	return ib
+}
+
+// QuoteIdents quotes the table and every column name set in INSERT INTO and RETURNING,
+// e.g. "demo.user" is built as `"demo"."user"`.
+// Names like "*" or names already quoted by `Ident` are kept as they are.
+// Conflict targets and assignments of ON CONFLICT are not quoted.
+func (ib *InsertBuilder) QuoteIdents() *InsertBuilder {
+	ib.quoteIdents = true
+	return ib
+}
+
+// Clone returns a deep copy of ib.
+// The copy can be modified and built independently.
+func (ib *InsertBuilder) Clone() *InsertBuilder {
+	clone := *ib
+	clone.args = ib.args.Clone()
+	clone.Cond.Args = clone.args
+	clone.returning = cloneStrings(ib.returning)
+	clone.onConflict = cloneStrings(ib.onConflict)
+	clone.assignments = cloneStrings(ib.assignments)
+	clone.cols = cloneStrings(ib.cols)
+	clone.conflictWhereExprs = cloneStrings(ib.conflictWhereExprs)
+	clone.updateWhereExprs = cloneStrings(ib.updateWhereExprs)
+
+	if ib.values != nil {
+		clone.values = make([][]string, 0, len(ib.values))
+
+		for _, v := range ib.values {
+			clone.values = append(clone.values, cloneStrings(v))
+		}
+	}
+
+	return &clone
+}
+
+func (ib *InsertBuilder) cloneBuilder() Builder {
+	return ib.Clone()
+}
+
+// Build returns compiled INSERT string and args.
 // They can be used in `DB#Query` of package `database/sql` directly.
 func (ib *InsertBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
+	return ib.args.Compile(ib.format(), initialArg...)
+}
+
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (ib *InsertBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = ib.args.CompileE(ib.format(), initialArg...)
+	return sql, args, errors.Join(ib.Validate(), err)
+}
+
+// Validate reports an INSERT without table or values,
+// with rows of values not matching columns,
+// with more than one source of rows among VALUES, SELECT and DEFAULT VALUES,
+// or with an ON CONFLICT missing its action or the conflict target required by DO UPDATE.
+func (ib *InsertBuilder) Validate() error {
+	var errs []error
+
+	if ib.table == "" {
+		errs = append(errs, fmt.Errorf("%w in INSERT", ErrMissingTable))
+	}
+
+	errs = append(errs, ib.validateOnConflict()...)
+
+	if ib.defaultValues {
+		if len(ib.cols) > 0 || len(ib.values) > 0 || ib.selectVar != "" {
+			errs = append(errs, fmt.Errorf("%w in INSERT: DEFAULT VALUES cannot be used with columns, VALUES or SELECT", ErrInvalidClause))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if ib.selectVar != "" {
+		if len(ib.values) > 0 {
+			errs = append(errs, fmt.Errorf("%w in INSERT: SELECT cannot be used with VALUES", ErrInvalidClause))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if len(ib.values) == 0 {
+		errs = append(errs, fmt.Errorf("%w in INSERT", ErrMissingValues))
+	}
+
+	for i, row := range ib.values {
+		if len(ib.cols) > 0 && len(row) != len(ib.cols) {
+			errs = append(errs, fmt.Errorf("%w in INSERT: row %v has %v values for %v columns", ErrValueCount, i, len(row), len(ib.cols)))
+		} else if len(ib.cols) == 0 && len(row) != len(ib.values[0]) {
+			errs = append(errs, fmt.Errorf("%w in INSERT: row %v has %v values but row 0 has %v values", ErrValueCount, i, len(row), len(ib.values[0])))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ib *InsertBuilder) validateOnConflict() (errs []error) {
+	hasTarget := len(ib.onConflict) != 0 || ib.onConstraint != ""
+
+	if !ib.doNothing && !ib.doUpdate {
+		if hasTarget || len(ib.conflictWhereExprs) != 0 || len(ib.updateWhereExprs) != 0 {
+			errs = append(errs, fmt.Errorf("%w in INSERT: call DoNothing or DoUpdate", ErrMissingConflictAction))
+		}
+
+		return
+	}
+
+	if ib.doUpdate {
+		if !hasTarget {
+			errs = append(errs, fmt.Errorf("%w in INSERT: ON CONFLICT DO UPDATE requires OnConflict or OnConstraint", ErrMissingConflictTarget))
+		}
+
+		if len(ib.assignments) == 0 {
+			errs = append(errs, fmt.Errorf("%w in ON CONFLICT DO UPDATE", ErrEmptySet))
+		}
+	}
+
+	return
+}
+
+// format returns INSERT in the format of `Args#Compile`.
+func (ib *InsertBuilder) format() string {
 	buf := &strings.Builder{}
+
+	if ib.cteVar != "" {
+		buf.WriteString(ib.cteVar)
+		buf.WriteRune(' ')
+	}
+
 	buf.WriteString(ib.verb)
 	buf.WriteString(" INTO ")
-	buf.WriteString(ib.table)
+	buf.WriteString(quoteIdentName(ib.quoteIdents, ib.table))
 
-	if len(ib.cols) > 0 {
-		buf.WriteString(" (")
-		buf.WriteString(strings.Join(ib.cols, ", "))
-		buf.WriteString(")")
-	}
+	if ib.defaultValues {
+		buf.WriteString(" DEFAULT VALUES")
+	} else {
+		if len(ib.cols) > 0 {
+			buf.WriteString(" (")
+			buf.WriteString(strings.Join(quoteIdentNames(ib.quoteIdents, ib.cols), ", "))
+			buf.WriteString(")")
+		}
 
-	buf.WriteString(" VALUES ")
-	values := make([]string, 0, len(ib.values))
+		if ib.selectVar != "" {
+			buf.WriteRune(' ')
+			buf.WriteString(ib.selectVar)
+		} else {
+			buf.WriteString(" VALUES ")
+			values := make([]string, 0, len(ib.values))
 
-	for _, v := range ib.values {
-		values = append(values, fmt.Sprintf("(%v)", strings.Join(v, ", ")))
+			for _, v := range ib.values {
+				values = append(values, fmt.Sprintf("(%v)", strings.Join(v, ", ")))
+			}
+
+			buf.WriteString(strings.Join(values, ", "))
+		}
 	}
 
-	buf.WriteString(strings.Join(values, ", "))
+	if len(ib.onConflict) != 0 || ib.onConstraint != "" || ib.doNothing || ib.doUpdate {
+		buf.WriteString(" ON CONFLICT")
 
-	if len(ib.onConflict) != 0 {
-		buf.WriteString(" ON CONFLICT (")
-		buf.WriteString(strings.Join(ib.onConflict, ", "))
-		buf.WriteString(")")
+		if ib.onConstraint != "" {
+			buf.WriteString(" ON CONSTRAINT ")
+			buf.WriteString(ib.onConstraint)
+		} else if len(ib.onConflict) != 0 {
+			buf.WriteString(" (")
+			buf.WriteString(strings.Join(ib.onConflict, ", "))
+			buf.WriteString(")")
 
-		if len(ib.assignments) != 0 {
+			if len(ib.conflictWhereExprs) != 0 {
+				buf.WriteString(" WHERE ")
+				buf.WriteString(strings.Join(ib.conflictWhereExprs, " AND "))
+			}
+		}
+
+		if ib.doNothing {
+			buf.WriteString(" DO NOTHING")
+		} else if ib.doUpdate {
 			buf.WriteString(" DO UPDATE SET ")
 			buf.WriteString(strings.Join(ib.assignments, ", "))
+
+			if len(ib.updateWhereExprs) != 0 {
+				buf.WriteString(" WHERE ")
+				buf.WriteString(strings.Join(ib.updateWhereExprs, " AND "))
+			}
 		}
 	}
 
 	if len(ib.returning) != 0 {
 		buf.WriteString(" RETURNING ")
-		buf.WriteString(strings.Join(ib.returning, ", "))
+		buf.WriteString(strings.Join(quoteIdentNames(ib.quoteIdents, ib.returning), ", "))
 	}
 
-	return ib.args.Compile(buf.String(), initialArg...)
+	return buf.String()
 }
 
 // Var returns a placeholder for value.
@@ -65,3 +65,114 @@ func TestInsert5(t *testing.T) {
 	assert.Equal(t, "INSERT INTO demo.user (id, name, status, created_at, updated_at) VALUES ($1, $2, $3, $4) ON CONFLICT (id, name) DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at", result)
 	assert.Equal(t, []interface{}{1, "Charmy Liu", 1, 1234567890}, args)
 }
+
+func TestInsertOnConstraint(t *testing.T) {
+	ib := NewInsertBuilder()
+	ib.InsertInto("demo.document AS t")
+	ib.Cols("id", "body", "version")
+	ib.Values(1, "hello", 3)
+	ib.OnConstraint("document_pkey")
+	ib.DoUpdate(ib.Set("body"), ib.Set("version"), ib.Assign("updated_by", "sync"))
+	ib.DoUpdateWhere("t.version < EXCLUDED.version", ib.NE("t.status", "locked"))
+	ib.Returning("id")
+
+	result, args := ib.Build()
+	assert.Equal(t, "INSERT INTO demo.document AS t (id, body, version) VALUES ($1, $2, $3) ON CONFLICT ON CONSTRAINT document_pkey DO UPDATE SET body = EXCLUDED.body, version = EXCLUDED.version, updated_by = $4 WHERE t.version < EXCLUDED.version AND t.status <> $5 RETURNING id", result)
+	assert.Equal(t, []interface{}{1, "hello", 3, "sync", "locked"}, args)
+}
+
+func TestInsertOnConflictDoNothing(t *testing.T) {
+	ib := NewInsertBuilder()
+	ib.InsertInto("demo.user")
+	ib.Cols("email", "name")
+	ib.Values("a@example.com", "A")
+	ib.OnConflict("(lower(email))")
+	ib.OnConflictWhere(ib.IsNull("deleted_at"), ib.EQ("tenant_id", 7))
+	ib.DoNothing()
+
+	result, args := ib.Build()
+	assert.Equal(t, "INSERT INTO demo.user (email, name) VALUES ($1, $2) ON CONFLICT ((lower(email))) WHERE deleted_at IS NULL AND tenant_id = $3 DO NOTHING", result)
+	assert.Equal(t, []interface{}{"a@example.com", "A", 7}, args)
+
+	result, _ = InsertInto("demo.user").Cols("email").Values("b@example.com").DoNothing().Build()
+	assert.Equal(t, "INSERT INTO demo.user (email) VALUES ($1) ON CONFLICT DO NOTHING", result)
+}
+
+func TestInsertOnConflictValidate(t *testing.T) {
+	result, _, err := InsertInto("demo.user").Cols("id", "name").Values(1, "a").OnConflict("id").BuildE()
+	assert.ErrorIs(t, err, ErrMissingConflictAction)
+	assert.Equal(t, "INSERT INTO demo.user (id, name) VALUES ($1, $2) ON CONFLICT (id)", result)
+
+	ib := InsertInto("demo.user").Cols("id", "name").Values(1, "a")
+	ib.DoUpdate(ib.Set("name"))
+	result, _, err = ib.BuildE()
+	assert.ErrorIs(t, err, ErrMissingConflictTarget)
+	assert.Equal(t, "INSERT INTO demo.user (id, name) VALUES ($1, $2) ON CONFLICT DO UPDATE SET name = EXCLUDED.name", result)
+
+	err = InsertInto("demo.user").Cols("id").Values(1).OnConstraint("user_pkey").DoUpdate().Validate()
+	assert.ErrorIs(t, err, ErrEmptySet)
+
+	err = InsertInto("demo.user").Cols("id").Values(1).OnConflict("id").DoUpdate("name = 'a'").Validate()
+	assert.NoError(t, err)
+
+	err = InsertInto("demo.user").Cols("id").Values(1).DoNothing().Validate()
+	assert.NoError(t, err)
+}
+
+func TestInsertSelect(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id", "name", sb.Var("archived")).
+		From("demo.user").
+		Where(sb.LT("last_login", "2020-01-01"))
+
+	ib := NewInsertBuilder()
+	ib.InsertInto("demo.user_archive")
+	ib.Cols("id", "name", "reason")
+	ib.Select(sb)
+	ib.OnConflict("id").DoNothing()
+	ib.Returning("id")
+
+	result, args := ib.Build(99)
+	assert.Equal(t, "INSERT INTO demo.user_archive (id, name, reason) SELECT id, name, $2 FROM demo.user WHERE last_login < $3 ON CONFLICT (id) DO NOTHING RETURNING id", result)
+	assert.Equal(t, []interface{}{99, "archived", "2020-01-01"}, args)
+}
+
+func TestInsertDefault(t *testing.T) {
+	result, args := InsertInto("demo.counter").DefaultValues().Returning("id").Build()
+	assert.Equal(t, "INSERT INTO demo.counter DEFAULT VALUES RETURNING id", result)
+	assert.Empty(t, args)
+
+	result, args = InsertInto("demo.user").
+		Cols("id", "name", "created_at").
+		Values(Default(), "A", Default()).
+		Values(Default(), "B", "2023-01-01").
+		Build()
+	assert.Equal(t, "INSERT INTO demo.user (id, name, created_at) VALUES (DEFAULT, $1, DEFAULT), (DEFAULT, $2, $3)", result)
+	assert.Equal(t, []interface{}{"A", "B", "2023-01-01"}, args)
+}
+
+func TestInsertQuoteIdents(t *testing.T) {
+	result, args := InsertInto("demo.user").
+		Cols("id", "Name").
+		Values(1, "a").
+		Returning("id").
+		QuoteIdents().
+		Build()
+
+	assert.Equal(t, `INSERT INTO "demo"."user" ("id", "Name") VALUES ($1, $2) RETURNING "id"`, result)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+}
+
+func TestInsertClone(t *testing.T) {
+	base := InsertInto("demo.user").Cols("id", "name").Values(1, "a")
+	clone := base.Clone().Values(2, "b")
+	base.Returning("id")
+
+	result, args := base.Build()
+	assert.Equal(t, "INSERT INTO demo.user (id, name) VALUES ($1, $2) RETURNING id", result)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+
+	result, args = clone.Build()
+	assert.Equal(t, "INSERT INTO demo.user (id, name) VALUES ($1, $2), ($3, $4)", result)
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+}
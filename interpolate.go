@@ -0,0 +1,359 @@
+package pgsql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInterpolateNotSupported is returned by Interpolate when an arg cannot be rendered as a literal.
+var ErrInterpolateNotSupported = errors.New("pgsql: interpolation not supported")
+
+// Interpolate compiles format with initialValue and inlines all args in the result.
+// See doc in `Interpolate` for details.
+func (args *Args) Interpolate(format string, initialValue ...interface{}) (string, error) {
+	query, values, err := args.CompileE(format, initialValue...)
+
+	if err != nil {
+		return "", err
+	}
+
+	return Interpolate(query, values)
+}
+
+// Interpolate replaces `$n` placeholders in sql with PostgreSQL literals of args,
+// e.g. the sql and args returned by `Build` or `Args#Compile`.
+//
+// The result is meant for logging and debugging only.
+// Always pass args to the database separately to run a query.
+//
+// Placeholders in quoted literals, quoted identifiers, dollar-quoted strings
+// and comments are left as they are. Args are rendered as following.
+//
+//	nil, nil pointer     NULL
+//	bool                 TRUE or FALSE
+//	integer, float       123, 1.5, 'NaN'::float8
+//	string               'it''s', or E'a\\b' if there is any backslash
+//	[]byte               E'\\x0102'::bytea
+//	json.RawMessage      '{"a":1}'
+//	time.Time            '2006-01-02 15:04:05.999999-07:00'
+//	slice, array         ARRAY[1, 2], or '{}' if empty
+//	driver.Valuer        literal of the value returned by `Value`
+//
+// Other types are reported with ErrInterpolateNotSupported.
+func Interpolate(sql string, args []interface{}) (string, error) {
+	return replacePlaceholders(sql, len(args), func(buf *strings.Builder, idx int) error {
+		return writeLiteral(buf, args[idx])
+	})
+}
+
+// replacePlaceholders calls write for every `$n` placeholder in sql with the 0-based index n-1
+// and replaces the placeholder with what's written to buf.
+// Placeholders in quoted literals, quoted identifiers, dollar-quoted strings and comments are skipped.
+// It's an error if any n is out of [1, count].
+func replacePlaceholders(sql string, count int, write func(buf *strings.Builder, idx int) error) (string, error) {
+	buf := &strings.Builder{}
+	buf.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		switch c := sql[i]; {
+		case c == '\'':
+			end := skipQuoted(sql, i, '\'', isEscapeString(sql, i))
+			buf.WriteString(sql[i:end])
+			i = end
+		case c == '"':
+			end := skipQuoted(sql, i, '"', false)
+			buf.WriteString(sql[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+
+			if end < 0 {
+				end = len(sql)
+			} else {
+				end += i + 1
+			}
+
+			buf.WriteString(sql[i:end])
+			i = end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := skipBlockComment(sql, i)
+			buf.WriteString(sql[i:end])
+			i = end
+		case c == '$' && (i == 0 || !isIdentChar(sql[i-1])):
+			end := i + 1
+
+			for ; end < len(sql) && '0' <= sql[end] && sql[end] <= '9'; end++ {
+				// Nothing.
+			}
+
+			if end > i+1 {
+				n, err := strconv.Atoi(sql[i+1 : end])
+
+				if err != nil || n < 1 || n > count {
+					return "", fmt.Errorf("%w: %v refers arg %v but there are %v args", ErrArgOutOfRange, sql[i:end], n, count)
+				}
+
+				if err := write(buf, n-1); err != nil {
+					return "", err
+				}
+
+				i = end
+				break
+			}
+
+			end = skipDollarQuoted(sql, i)
+			buf.WriteString(sql[i:end])
+			i = end
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// isEscapeString reports whether the quote at i starts an E'...' string.
+func isEscapeString(sql string, i int) bool {
+	if i == 0 || (sql[i-1] != 'E' && sql[i-1] != 'e') {
+		return false
+	}
+
+	return i == 1 || !isIdentChar(sql[i-2])
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+// skipQuoted returns the index after the closing quote of the quoted text starting at i.
+func skipQuoted(sql string, i int, quote byte, backslash bool) int {
+	for i++; i < len(sql); i++ {
+		if backslash && sql[i] == '\\' {
+			i++
+			continue
+		}
+
+		if sql[i] != quote {
+			continue
+		}
+
+		// A doubled quote is an escaped quote.
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(sql)
+}
+
+// skipBlockComment returns the index after the end of the block comment starting at i.
+// Block comments can be nested in PostgreSQL.
+func skipBlockComment(sql string, i int) int {
+	depth := 0
+
+	for i < len(sql) {
+		if strings.HasPrefix(sql[i:], "/*") {
+			depth++
+			i += 2
+		} else if strings.HasPrefix(sql[i:], "*/") {
+			depth--
+			i += 2
+
+			if depth == 0 {
+				return i
+			}
+		} else {
+			i++
+		}
+	}
+
+	return len(sql)
+}
+
+// skipDollarQuoted returns the index after the dollar-quoted string starting at i.
+// If there is no valid tag at i, it returns the index after the `$`.
+func skipDollarQuoted(sql string, i int) int {
+	end := i + 1
+
+	for ; end < len(sql) && sql[end] != '$'; end++ {
+		c := sql[end]
+
+		if c != '_' && !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80 || end > i+1 && '0' <= c && c <= '9') {
+			return i + 1
+		}
+	}
+
+	if end == len(sql) {
+		return i + 1
+	}
+
+	tag := sql[i : end+1]
+	closing := strings.Index(sql[end+1:], tag)
+
+	if closing < 0 {
+		return len(sql)
+	}
+
+	return end + 1 + closing + len(tag)
+}
+
+var typeOfTime = reflect.TypeOf(time.Time{})
+
+func writeLiteral(buf *strings.Builder, arg interface{}) error {
+	switch a := arg.(type) {
+	case nil:
+		buf.WriteString("NULL")
+	case bool:
+		if a {
+			buf.WriteString("TRUE")
+		} else {
+			buf.WriteString("FALSE")
+		}
+	case string:
+		buf.WriteString(quoteLiteral(a))
+	case json.RawMessage:
+		if a == nil {
+			buf.WriteString("NULL")
+		} else {
+			buf.WriteString(quoteLiteral(string(a)))
+		}
+	case []byte:
+		if a == nil {
+			buf.WriteString("NULL")
+		} else {
+			// An escape string means the same regardless of standard_conforming_strings.
+			buf.WriteString(`E'\\x`)
+			buf.WriteString(hex.EncodeToString(a))
+			buf.WriteString("'::bytea")
+		}
+	case time.Time:
+		buf.WriteString(quoteLiteral(a.Format("2006-01-02 15:04:05.999999Z07:00")))
+	case driver.Valuer:
+		if v := reflect.ValueOf(a); v.Kind() == reflect.Ptr && v.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+
+		value, err := a.Value()
+
+		if err != nil {
+			return err
+		}
+
+		return writeLiteral(buf, value)
+	default:
+		return writeValueLiteral(buf, reflect.ValueOf(arg))
+	}
+
+	return nil
+}
+
+func writeValueLiteral(buf *strings.Builder, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+
+		return writeLiteral(buf, v.Elem().Interface())
+	case reflect.Bool:
+		return writeLiteral(buf, v.Bool())
+	case reflect.String:
+		return writeLiteral(buf, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeNumber(buf, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+
+		switch {
+		case math.IsNaN(f):
+			buf.WriteString("'NaN'::float8")
+		case math.IsInf(f, 1):
+			buf.WriteString("'Infinity'::float8")
+		case math.IsInf(f, -1):
+			buf.WriteString("'-Infinity'::float8")
+		default:
+			writeNumber(buf, strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return writeLiteral(buf, v.Bytes())
+		}
+
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+
+		if v.Len() == 0 {
+			buf.WriteString("'{}'")
+			return nil
+		}
+
+		buf.WriteString("ARRAY[")
+
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+
+			if err := writeLiteral(buf, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("]")
+	case reflect.Struct:
+		if v.Type().ConvertibleTo(typeOfTime) {
+			return writeLiteral(buf, v.Convert(typeOfTime).Interface())
+		}
+
+		return fmt.Errorf("%w: %v", ErrInterpolateNotSupported, v.Type())
+	default:
+		return fmt.Errorf("%w: %v", ErrInterpolateNotSupported, v.Type())
+	}
+
+	return nil
+}
+
+// writeNumber writes a formatted number to buf.
+// A negative number is surrounded by parens,
+// so that "x-$1" with -3 is "x-(-3)" rather than "x--3", which starts a comment.
+func writeNumber(buf *strings.Builder, n string) {
+	if strings.HasPrefix(n, "-") {
+		buf.WriteString("(")
+		buf.WriteString(n)
+		buf.WriteString(")")
+		return
+	}
+
+	buf.WriteString(n)
+}
+
+// quoteLiteral quotes s as a string literal.
+// Backslashes are escaped in an escape string like E'a\\b' so that the literal means the same
+// regardless of standard_conforming_strings.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+
+	if strings.Contains(s, `\`) {
+		return `E'` + strings.ReplaceAll(s, `\`, `\\`) + "'"
+	}
+
+	return "'" + s + "'"
+}
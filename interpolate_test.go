@@ -0,0 +1,105 @@
+package pgsql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id", "name").From("demo.user").Where(
+		sb.EQ("name", "O'Reilly"),
+		sb.In("status", 1, 2),
+		sb.GT("score", 1.5),
+		sb.EQ("active", true),
+		sb.IsNull("deleted_at"),
+	)
+
+	query, args := sb.Build()
+	result, err := Interpolate(query, args)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM demo.user WHERE name = 'O''Reilly' AND status IN (1, 2) AND score > 1.5 AND active = TRUE AND deleted_at IS NULL", result)
+}
+
+func TestInterpolateTypes(t *testing.T) {
+	var nilPtr *int
+	n := 42
+	ts := time.Date(2023, 4, 5, 6, 7, 8, 123456000, time.UTC)
+	cases := []struct {
+		arg      interface{}
+		expected string
+	}{
+		{nil, "NULL"},
+		{nilPtr, "NULL"},
+		{&n, "42"},
+		{int8(-3), "(-3)"},
+		{-1.5, "(-1.5)"},
+		{uint64(7), "7"},
+		{false, "FALSE"},
+		{math.NaN(), "'NaN'::float8"},
+		{math.Inf(-1), "'-Infinity'::float8"},
+		{`C:\temp`, `E'C:\\temp'`},
+		{[]byte{0x01, 0xab}, `E'\\x01ab'::bytea`},
+		{json.RawMessage(`{"a":"b'c"}`), `'{"a":"b''c"}'`},
+		{ts, "'2023-04-05 06:07:08.123456Z'"},
+		{ts.In(time.FixedZone("", 3*3600)), "'2023-04-05 09:07:08.123456+03:00'"},
+		{[]int{1, 2}, "ARRAY[1, 2]"},
+		{[]string{"a", "b'"}, "ARRAY['a', 'b''']"},
+		{[2][]int{{1}, {2}}, "ARRAY[ARRAY[1], ARRAY[2]]"},
+		{[]string{}, "'{}'"},
+		{sql.NullString{String: "a", Valid: true}, "'a'"},
+		{sql.NullInt64{}, "NULL"},
+	}
+
+	for _, c := range cases {
+		result, err := Interpolate("SELECT $1", []interface{}{c.arg})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "SELECT "+c.expected, result)
+		}
+	}
+}
+
+func TestInterpolateNegative(t *testing.T) {
+	result, err := Interpolate("SELECT x-$1, y-$2 FROM t", []interface{}{-3, -0.5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT x-(-3), y-(-0.5) FROM t", result)
+}
+
+func TestInterpolateSkip(t *testing.T) {
+	query := `SELECT '$1', E'\'$1', "a$1", $$ $1 $$, $tag$ $1 $tag$, a$1 -- $1
+/* /* $1 */ $1 */ FROM t WHERE id = $1 AND name = $2`
+	result, err := Interpolate(query, []interface{}{1, "a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT '$1', E'\'$1', "a$1", $$ $1 $$, $tag$ $1 $tag$, a$1 -- $1
+/* /* $1 */ $1 */ FROM t WHERE id = 1 AND name = 'a'`, result)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	_, err := Interpolate("SELECT $2", []interface{}{1})
+	assert.ErrorIs(t, err, ErrArgOutOfRange)
+
+	_, err = Interpolate("SELECT $1", []interface{}{struct{}{}})
+	assert.ErrorIs(t, err, ErrInterpolateNotSupported)
+}
+
+func TestArgsInterpolate(t *testing.T) {
+	args := &Args{}
+	args.Add(Named("id", 1))
+	args.Add("a")
+
+	result, err := args.Interpolate("SELECT * FROM t WHERE id = ${id} AND parent_id = ${id} AND name = $1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = 1 AND parent_id = 1 AND name = 'a'", result)
+
+	_, err = args.Interpolate("SELECT ${name}")
+	assert.ErrorIs(t, err, ErrUnresolvedName)
+}
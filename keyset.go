@@ -0,0 +1,206 @@
+package pgsql
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded
+// or doesn't match key columns of a Keyset.
+var ErrInvalidCursor = errors.New("pgsql: invalid cursor")
+
+// NewKeyset creates a new keyset.
+func NewKeyset() *Keyset {
+	return &Keyset{}
+}
+
+// Keyset describes ordered key columns of keyset pagination, a.k.a. seek method.
+//
+// Key columns must identify a row uniquely and must not be NULL,
+// e.g. `NewKeyset().Desc("created_at").Desc("id")`.
+// Use `SelectBuilder#Seek` to select the page after the last row set by `After` or `Decode`.
+type Keyset struct {
+	cols   []orderByCol
+	values []interface{}
+}
+
+// Asc adds a key column sorted in ascending order.
+func (ks *Keyset) Asc(col string) *Keyset {
+	ks.cols = append(ks.cols, orderByCol{expr: col, order: "ASC"})
+	return ks
+}
+
+// Desc adds a key column sorted in descending order.
+func (ks *Keyset) Desc(col string) *Keyset {
+	ks.cols = append(ks.cols, orderByCol{expr: col, order: "DESC"})
+	return ks
+}
+
+// After sets key values of the last row in previous page.
+// No value means the first page.
+func (ks *Keyset) After(value ...interface{}) error {
+	if len(value) != 0 && len(value) != len(ks.cols) {
+		return fmt.Errorf("%w: %d values for %d key columns", ErrInvalidCursor, len(value), len(ks.cols))
+	}
+
+	ks.values = value
+	return nil
+}
+
+// Decode decodes cursor created by `EncodeCursor` and sets key values of the last row in previous page.
+// An empty cursor means the first page.
+func (ks *Keyset) Decode(cursor string) error {
+	values, err := DecodeCursor(cursor)
+
+	if err != nil {
+		return err
+	}
+
+	return ks.After(values...)
+}
+
+// Seek orders SELECT by key columns in ks and selects rows after the last row set in ks.
+//
+// If all key columns are sorted in the same order, it builds a row comparison like
+//
+//	WHERE (a, b) > ($1, $2) ORDER BY a ASC, b ASC
+//
+// Otherwise, it builds an expanded comparison like
+//
+//	WHERE (a > $1 OR (a = $1 AND b < $2)) ORDER BY a ASC, b DESC
+//
+// Each key value is added once and its placeholder is shared by all comparisons.
+func (sb *SelectBuilder) Seek(ks *Keyset) *SelectBuilder {
+	if len(ks.values) != 0 {
+		sb.Where(ks.where(sb.Var))
+	}
+
+	for _, col := range ks.cols {
+		sb.orderBy.add(col.order, col.expr)
+	}
+
+	return sb
+}
+
+func (ks *Keyset) where(variable func(interface{}) string) string {
+	order := ks.cols[0].order
+	mixed := false
+
+	for _, col := range ks.cols[1:] {
+		if col.order != order {
+			mixed = true
+			break
+		}
+	}
+
+	values := make([]string, 0, len(ks.values))
+
+	for _, v := range ks.values {
+		values = append(values, variable(v))
+	}
+
+	if !mixed {
+		cols := make([]string, 0, len(ks.cols))
+
+		for _, col := range ks.cols {
+			cols = append(cols, col.expr)
+		}
+
+		op := " > "
+
+		if order == "DESC" {
+			op = " < "
+		}
+
+		if len(cols) == 1 {
+			return cols[0] + op + values[0]
+		}
+
+		return "(" + strings.Join(cols, ", ") + ")" + op + "(" + strings.Join(values, ", ") + ")"
+	}
+
+	ors := make([]string, 0, len(ks.cols))
+
+	for i, col := range ks.cols {
+		ands := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			ands = append(ands, ks.cols[j].expr+" = "+values[j])
+		}
+
+		op := " > "
+
+		if col.order == "DESC" {
+			op = " < "
+		}
+
+		ands = append(ands, col.expr+op+values[i])
+
+		if len(ands) == 1 {
+			ors = append(ors, ands[0])
+		} else {
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+// EncodeCursor encodes key values of a row to an opaque URL-safe cursor.
+//
+// Values are encoded in JSON. After decoding, numbers are int64 or float64
+// and other values are decoded as JSON values, e.g. time.Time becomes an RFC 3339 string,
+// which PostgreSQL accepts for timestamp columns.
+func EncodeCursor(value ...interface{}) (string, error) {
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes key values encoded by `EncodeCursor`.
+// An empty cursor is decoded to no value.
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values []interface{}
+
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	for i, v := range values {
+		n, ok := v.(json.Number)
+
+		if !ok {
+			continue
+		}
+
+		if iv, err := n.Int64(); err == nil {
+			values[i] = iv
+		} else if fv, err := n.Float64(); err == nil {
+			values[i] = fv
+		} else {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,72 @@
+package pgsql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetFirstPage(t *testing.T) {
+	ks := NewKeyset().Desc("created_at").Desc("id")
+	assert.NoError(t, ks.Decode(""))
+
+	result, args := Select("*").From("demo.post").Seek(ks).Limit(20).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.post ORDER BY created_at DESC, id DESC LIMIT 20", result)
+	assert.Empty(t, args)
+}
+
+func TestKeysetRowComparison(t *testing.T) {
+	ks := NewKeyset().Asc("created_at").Asc("id")
+	assert.NoError(t, ks.After("2023-01-01T00:00:00Z", 42))
+
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.post").Where(sb.EQ("tenant_id", 7)).Seek(ks).Limit(20)
+
+	result, args := sb.Build()
+
+	assert.Equal(t, "SELECT * FROM demo.post WHERE tenant_id = $1 AND (created_at, id) > ($2, $3) ORDER BY created_at ASC, id ASC LIMIT 20", result)
+	assert.Equal(t, []interface{}{7, "2023-01-01T00:00:00Z", 42}, args)
+
+	ks = NewKeyset().Desc("id")
+	assert.NoError(t, ks.After(42))
+
+	result, args = Select("*").From("demo.post").Seek(ks).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.post WHERE id < $1 ORDER BY id DESC", result)
+	assert.Equal(t, []interface{}{42}, args)
+}
+
+func TestKeysetMixedOrder(t *testing.T) {
+	ks := NewKeyset().Desc("score").Asc("name").Asc("id")
+	assert.NoError(t, ks.After(99.5, "bob", 3))
+
+	result, args := Select("*").From("demo.player").Seek(ks).Limit(10).Build()
+
+	assert.Equal(t, "SELECT * FROM demo.player WHERE (score < $1 OR (score = $1 AND name > $2) OR (score = $1 AND name = $2 AND id > $3)) ORDER BY score DESC, name ASC, id ASC LIMIT 10", result)
+	assert.Equal(t, []interface{}{99.5, "bob", 3}, args)
+
+	result, args = Select("*").From("demo.player").Seek(ks).Build(1)
+	assert.Equal(t, "SELECT * FROM demo.player WHERE (score < $2 OR (score = $2 AND name > $3) OR (score = $2 AND name = $3 AND id > $4)) ORDER BY score DESC, name ASC, id ASC", result)
+	assert.Equal(t, []interface{}{1, 99.5, "bob", 3}, args)
+}
+
+func TestKeysetCursor(t *testing.T) {
+	cursor, err := EncodeCursor("2023-01-01T00:00:00Z", 42, 1.5, true, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, cursor, "=")
+	assert.NotContains(t, cursor, "+")
+	assert.NotContains(t, cursor, "/")
+
+	values, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"2023-01-01T00:00:00Z", int64(42), 1.5, true, nil}, values)
+
+	ks := NewKeyset().Asc("id")
+	err = ks.Decode(cursor)
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+
+	_, err = DecodeCursor("not a cursor")
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+}
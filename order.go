@@ -0,0 +1,116 @@
+package pgsql
+
+import (
+	"strings"
+)
+
+// orderByCol is an expression in ORDER BY with its own sort options.
+type orderByCol struct {
+	expr  string
+	order string // ASC, DESC or USING operator.
+	nulls string // FIRST or LAST.
+}
+
+func (col orderByCol) String() string {
+	if col.order == "" && col.nulls == "" {
+		return col.expr
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString(col.expr)
+
+	if col.order != "" {
+		buf.WriteRune(' ')
+		buf.WriteString(col.order)
+	}
+
+	if col.nulls != "" {
+		buf.WriteString(" NULLS ")
+		buf.WriteString(col.nulls)
+	}
+
+	return buf.String()
+}
+
+// orderByClause is a list of ORDER BY expressions.
+// Sort options set by modifiers like `Desc` or `NullsLast` of a builder
+// apply to the expressions added by the latest OrderBy* call.
+type orderByClause struct {
+	cols []orderByCol
+	last int
+}
+
+func (obc *orderByClause) add(order string, col ...string) {
+	obc.last = len(obc.cols)
+
+	for _, c := range col {
+		obc.cols = append(obc.cols, orderByCol{
+			expr:  c,
+			order: order,
+		})
+	}
+}
+
+func (obc *orderByClause) setOrder(order string) {
+	for i := obc.last; i < len(obc.cols); i++ {
+		obc.cols[i].order = order
+	}
+}
+
+func (obc *orderByClause) setNulls(nulls string) {
+	for i := obc.last; i < len(obc.cols); i++ {
+		obc.cols[i].nulls = nulls
+	}
+}
+
+// clone returns a deep copy of obc.
+func (obc orderByClause) clone() orderByClause {
+	if obc.cols != nil {
+		obc.cols = append(make([]orderByCol, 0, len(obc.cols)), obc.cols...)
+	}
+
+	return obc
+}
+
+// quoted returns a copy of obc with expressions quoted by quoteIdentName if quote is true.
+func (obc orderByClause) quoted(quote bool) orderByClause {
+	if !quote {
+		return obc
+	}
+
+	cols := make([]orderByCol, 0, len(obc.cols))
+
+	for _, col := range obc.cols {
+		col.expr = quoteIdentName(true, col.expr)
+		cols = append(cols, col)
+	}
+
+	return orderByClause{
+		cols: cols,
+		last: obc.last,
+	}
+}
+
+func (obc *orderByClause) empty() bool {
+	return len(obc.cols) == 0
+}
+
+func (obc *orderByClause) String() string {
+	cols := make([]string, 0, len(obc.cols))
+
+	for _, col := range obc.cols {
+		cols = append(cols, col.String())
+	}
+
+	return strings.Join(cols, ", ")
+}
+
+// writeTo writes " ORDER BY ..." to buf if there is any expression.
+func (obc *orderByClause) writeTo(buf *strings.Builder) {
+	if obc.empty() {
+		return
+	}
+
+	buf.WriteString(" ORDER BY ")
+	buf.WriteString(obc.String())
+}
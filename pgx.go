@@ -0,0 +1,187 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrRewriteQuerySQL is returned by `RewriteQuery` of a builder if the sql is not empty.
+	ErrRewriteQuerySQL = errors.New("pgsql: sql must be empty when a builder rewrites the query")
+
+	// ErrNameConflict is returned by `Args#CompileNamedArgs` if a name in `${name}`
+	// is the same as the name of a positional value, e.g. "p2".
+	ErrNameConflict = errors.New("pgsql: named argument conflicts with positional argument")
+
+	// ErrInvalidName is returned by `Args#CompileNamedArgs` if a name in `${name}`
+	// cannot be a pgx named argument, which allows only letters, digits and underscores
+	// and cannot start with a digit.
+	ErrInvalidName = errors.New("pgsql: invalid pgx named argument")
+)
+
+// CompileNamedArgs is like CompileE but emits pgx named placeholders like `@name`
+// instead of `$n`, and returns values in a `pgx.NamedArgs`.
+//
+// A value referred by `${name}` is named by name.
+// Other values, including values in nested builders, are named by position like `@p1`, `@p2`.
+// If a name in `${name}` is the same as the name of a positional value, ErrNameConflict is returned.
+// If a name in `${name}` is not a valid pgx name like "tenant-id", ErrInvalidName is returned.
+//
+//	args.Add(Named("id", 1))
+//	args.Add("a")
+//	args.CompileNamedArgs("SELECT * FROM t WHERE id = ${id} AND name = $1")
+//	// query: "SELECT * FROM t WHERE id = @id AND name = @p2"
+//	// namedArgs: pgx.NamedArgs{"id": 1, "p2": "a"}
+func (args *Args) CompileNamedArgs(format string, initialValue ...interface{}) (query string, namedArgs pgx.NamedArgs, err error) {
+	query, values, names, err := args.compile(format, initialValue)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	userNames := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		userNames[name] = true
+	}
+
+	namedArgs = make(pgx.NamedArgs, len(values))
+	query, err = replacePlaceholders(query, len(values), func(buf *strings.Builder, idx int) error {
+		name, ok := names[idx]
+
+		if ok && !isPgxName(name) {
+			return fmt.Errorf("%w: ${%v}", ErrInvalidName, name)
+		}
+
+		if !ok {
+			name = "p" + strconv.Itoa(idx+1)
+
+			if userNames[name] {
+				return fmt.Errorf("%w: ${%v}", ErrNameConflict, name)
+			}
+		}
+
+		namedArgs[name] = values[idx]
+		buf.WriteRune('@')
+		buf.WriteString(name)
+		return nil
+	})
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return query, namedArgs, nil
+}
+
+// isPgxName reports whether name can be parsed as a whole by pgx after `@`.
+func isPgxName(name string) bool {
+	if name == "" || '0' <= name[0] && name[0] <= '9' {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		if !(c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// QueueBatch builds all builders and queues them in batch.
+// If any builder reports an error in `BuildE`, no builder is queued.
+//
+// Queued queries are returned in the same order as builders,
+// so that result handlers can be set by `QueuedQuery#Query`, `QueuedQuery#Exec`, etc.
+func QueueBatch(batch *pgx.Batch, builders ...Builder) ([]*pgx.QueuedQuery, error) {
+	type built struct {
+		sql  string
+		args []interface{}
+	}
+
+	queries := make([]built, 0, len(builders))
+	var errs []error
+
+	for _, b := range builders {
+		sql, args, err := BuildE(b)
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		queries = append(queries, built{sql, args})
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	queued := make([]*pgx.QueuedQuery, 0, len(queries))
+
+	for _, q := range queries {
+		queued = append(queued, batch.Queue(q.sql, q.args...))
+	}
+
+	return queued, nil
+}
+
+// rewriteQuery builds builder for `pgx.QueryRewriter`.
+// The args following the builder are used as initial args.
+func rewriteQuery(builder Builder, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	if sql != "" {
+		return "", nil, ErrRewriteQuerySQL
+	}
+
+	return BuildE(builder, args...)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that the builder can be used in pgx like `conn.Query(ctx, "", builder)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (cb *builder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(cb, sql, args)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that SELECT can be used in pgx like `conn.Query(ctx, "", sb)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (sb *SelectBuilder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(sb, sql, args)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that INSERT can be used in pgx like `conn.Exec(ctx, "", ib)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (ib *InsertBuilder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(ib, sql, args)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that UPDATE can be used in pgx like `conn.Exec(ctx, "", ub)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (ub *UpdateBuilder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(ub, sql, args)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that DELETE can be used in pgx like `conn.Exec(ctx, "", db)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (db *DeleteBuilder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(db, sql, args)
+}
+
+// RewriteQuery implements `pgx.QueryRewriter`,
+// so that set operations can be used in pgx like `conn.Query(ctx, "", ub)`.
+// The sql must be empty. Any arg following the builder is used as an initial arg in `BuildE`.
+func (ub *UnionBuilder) RewriteQuery(ctx context.Context, conn *pgx.Conn, sql string, args []interface{}) (newSQL string, newArgs []interface{}, err error) {
+	return rewriteQuery(ub, sql, args)
+}
@@ -0,0 +1,112 @@
+package pgsql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteQuery(t *testing.T) {
+	ctx := context.Background()
+	sb := NewSelectBuilder()
+	sb.Select("*").From("demo.user").Where(sb.EQ("id", 1))
+
+	var rewriter pgx.QueryRewriter = sb
+	sql, args, err := rewriter.RewriteQuery(ctx, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = rewriter.RewriteQuery(ctx, nil, "", []interface{}{"x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM demo.user WHERE id = $2", sql)
+	assert.Equal(t, []interface{}{"x", 1}, args)
+
+	_, _, err = rewriter.RewriteQuery(ctx, nil, "SELECT 1", nil)
+	assert.ErrorIs(t, err, ErrRewriteQuerySQL)
+
+	_, _, err = NewSelectBuilder().RewriteQuery(ctx, nil, "", nil)
+	assert.ErrorIs(t, err, ErrMissingColumns)
+
+	rewriters := []pgx.QueryRewriter{
+		NewInsertBuilder(),
+		NewUpdateBuilder(),
+		NewDeleteBuilder(),
+		NewUnionBuilder(),
+		Build("SELECT 1").(pgx.QueryRewriter),
+	}
+	assert.Len(t, rewriters, 5)
+}
+
+func TestQueueBatch(t *testing.T) {
+	ib := InsertInto("demo.user").Cols("name").Values("a")
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user").Set(ub.Assign("status", 1)).Where(ub.EQ("name", "a"))
+
+	batch := &pgx.Batch{}
+	queued, err := QueueBatch(batch, ib, ub)
+	assert.NoError(t, err)
+	assert.Len(t, queued, 2)
+	assert.Equal(t, 2, batch.Len())
+	assert.Equal(t, "INSERT INTO demo.user (name) VALUES ($1)", queued[0].SQL)
+	assert.Equal(t, []interface{}{"a"}, queued[0].Arguments)
+	assert.Equal(t, "UPDATE demo.user SET status = $1 WHERE name = $2", queued[1].SQL)
+	assert.Equal(t, []interface{}{1, "a"}, queued[1].Arguments)
+
+	_, err = QueueBatch(batch, ib, NewUpdateBuilder())
+	assert.ErrorIs(t, err, ErrMissingTable)
+	assert.Equal(t, 2, batch.Len())
+}
+
+func TestCompileNamedArgs(t *testing.T) {
+	sub := NewSelectBuilder()
+	sub.Select("id").From("demo.admin").Where(sub.GT("level", 3))
+
+	args := &Args{}
+	args.Add(Named("id", 1))
+	args.Add("a")
+	args.Add(sub)
+
+	query, named, err := args.CompileNamedArgs("SELECT * FROM t WHERE id = ${id} AND parent_id = ${id} AND name = $1 AND owner_id IN ($2) AND note <> '$$1'")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = @id AND parent_id = @id AND name = @p2 AND owner_id IN (SELECT id FROM demo.admin WHERE level > @p3) AND note <> '$1'", query)
+	assert.Equal(t, pgx.NamedArgs{"id": 1, "p2": "a", "p3": 3}, named)
+
+	rewritten, values, err := named.RewriteQuery(context.Background(), nil, query, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = $1 AND parent_id = $1 AND name = $2 AND owner_id IN (SELECT id FROM demo.admin WHERE level > $3) AND note <> '$1'", rewritten)
+	assert.Equal(t, []interface{}{1, "a", 3}, values)
+
+	_, _, err = args.CompileNamedArgs("SELECT ${name}")
+	assert.ErrorIs(t, err, ErrUnresolvedName)
+
+	args = &Args{}
+	args.Add("a")
+	args.Add(Named("p1", 1))
+
+	_, _, err = args.CompileNamedArgs("SELECT * FROM t WHERE name = $0 AND id = ${p1}")
+	assert.ErrorIs(t, err, ErrNameConflict)
+
+	// The positional value is named "p2" if ${p1} is the first value.
+	query, named, err = args.CompileNamedArgs("SELECT * FROM t WHERE id = ${p1} AND name = $0")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = @p1 AND name = @p2", query)
+	assert.Equal(t, pgx.NamedArgs{"p1": 1, "p2": "a"}, named)
+
+	for _, name := range []string{"tenant-id", "1st", "naïve"} {
+		args = &Args{}
+		args.Add(Named(name, 1))
+
+		_, _, err = args.CompileNamedArgs("SELECT * FROM t WHERE id = ${" + name + "}")
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+	}
+
+	args = &Args{}
+	args.Add(Named("_tenant_id2", 1))
+
+	query, _, err = args.CompileNamedArgs("SELECT * FROM t WHERE id = ${_tenant_id2}")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = @_tenant_id2", query)
+}
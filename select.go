@@ -1,6 +1,7 @@
 package pgsql
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -25,22 +26,27 @@ type SelectBuilder struct {
 	Cond
 
 	args    *Args
+	cteVar  string
 	forWhat string
+	forWait string
+	forOf   []string
 
-	order       string
 	havingExprs []string
 	joinTables  []string
 	joinExprs   [][]string
 	whereExprs  []string
 	joinOptions []JoinOption
 	groupByCols []string
-	orderByCols []string
+	orderBy     orderByClause
 	selectCols  []string
 	tables      []string
 	limit       int
 	offset      int
 
-	distinct bool
+	distinct       bool
+	distinctOnCols []string
+	windowDefs     []string
+	quoteIdents    bool
 }
 
 // NewSelectBuilder creates a new SELECT builder.
@@ -60,6 +66,12 @@ func newSelectBuilder() *SelectBuilder {
 	}
 }
 
+// With sets the WITH clause of SELECT.
+func (sb *SelectBuilder) With(cte *CTEBuilder) *SelectBuilder {
+	sb.cteVar = sb.Var(cte)
+	return sb
+}
+
 func Select(col ...string) *SelectBuilder {
 	return newSelectBuilder().Select(col...)
 }
@@ -69,6 +81,26 @@ func (sb *SelectBuilder) Select(col ...string) *SelectBuilder {
 	return sb
 }
 
+// Distinct marks this SELECT as DISTINCT.
+func (sb *SelectBuilder) Distinct() *SelectBuilder {
+	sb.distinct = true
+	return sb
+}
+
+// DistinctOn marks this SELECT as DISTINCT ON (col...).
+//
+// PostgreSQL requires the DISTINCT ON expressions to match the leftmost
+// ORDER BY expressions, e.g.
+//
+//	Select("*").From("t").DistinctOn("user_id").OrderBy("", "user_id", "created_at DESC")
+//
+// Validate reports ErrDistinctOnOrderBy if ORDER BY is set and does not start with them.
+func (sb *SelectBuilder) DistinctOn(col ...string) *SelectBuilder {
+	sb.distinct = true
+	sb.distinctOnCols = col
+	return sb
+}
+
 // From sets table names in SELECT.
 func (sb *SelectBuilder) From(table ...string) *SelectBuilder {
 	sb.tables = table
@@ -103,24 +135,64 @@ func (sb *SelectBuilder) GroupBy(col ...string) *SelectBuilder {
 	return sb
 }
 
-// OrderBy sets columns of ORDER BY in SELECT with the provided order.
+// Window adds a named window to WINDOW clause in SELECT.
+// The window can be referenced by name in OVER, e.g. "rank() OVER w".
+//
+// It builds a WINDOW clause like
+//
+//	WINDOW name AS (window)
+func (sb *SelectBuilder) Window(name string, window *Window) *SelectBuilder {
+	sb.windowDefs = append(sb.windowDefs, fmt.Sprintf("%s AS (%s)", name, window.String()))
+	return sb
+}
+
+// OrderBy adds columns of ORDER BY in SELECT with the provided order.
+// The order applies to each of the columns, e.g. `OrderBy("DESC", "a", "b")`
+// builds "ORDER BY a DESC, b DESC".
 func (sb *SelectBuilder) OrderBy(order string, col ...string) *SelectBuilder {
-	sb.order = order
-	sb.orderByCols = append(sb.orderByCols, col...)
+	sb.orderBy.add(order, col...)
 	return sb
 }
 
-// OrderByAsc sets columns of ORDER BY ASC in SELECT.
+// OrderByAsc adds columns of ORDER BY ASC in SELECT.
 func (sb *SelectBuilder) OrderByAsc(col ...string) *SelectBuilder {
-	sb.order = "ASC"
-	sb.orderByCols = append(sb.orderByCols, col...)
+	sb.orderBy.add("ASC", col...)
 	return sb
 }
 
-// OrderByDesc sets columns of ORDER BY DESC in SELECT.
+// OrderByDesc adds columns of ORDER BY DESC in SELECT.
 func (sb *SelectBuilder) OrderByDesc(col ...string) *SelectBuilder {
-	sb.order = "DESC"
-	sb.orderByCols = append(sb.orderByCols, col...)
+	sb.orderBy.add("DESC", col...)
+	return sb
+}
+
+// OrderByUsing adds columns of ORDER BY USING op in SELECT.
+func (sb *SelectBuilder) OrderByUsing(op string, col ...string) *SelectBuilder {
+	sb.orderBy.add("USING "+op, col...)
+	return sb
+}
+
+// Asc sets order of columns added by the latest OrderBy* call to ASC.
+func (sb *SelectBuilder) Asc() *SelectBuilder {
+	sb.orderBy.setOrder("ASC")
+	return sb
+}
+
+// Desc sets order of columns added by the latest OrderBy* call to DESC.
+func (sb *SelectBuilder) Desc() *SelectBuilder {
+	sb.orderBy.setOrder("DESC")
+	return sb
+}
+
+// NullsFirst sorts NULLs first in columns added by the latest OrderBy* call.
+func (sb *SelectBuilder) NullsFirst() *SelectBuilder {
+	sb.orderBy.setNulls("FIRST")
+	return sb
+}
+
+// NullsLast sorts NULLs last in columns added by the latest OrderBy* call.
+func (sb *SelectBuilder) NullsLast() *SelectBuilder {
+	sb.orderBy.setNulls("LAST")
 	return sb
 }
 
@@ -130,18 +202,6 @@ func (sb *SelectBuilder) BuilderAs(builder Builder, alias string) string {
 	return fmt.Sprintf("(%s) AS %s", sb.Var(builder), alias)
 }
 
-// // Asc sets order of ORDER BY to ASC.
-// func (sb *SelectBuilder) Asc() *SelectBuilder {
-// 	sb.order = "ASC"
-// 	return sb
-// }
-
-// // Desc sets order of ORDER BY to DESC.
-// func (sb *SelectBuilder) Desc() *SelectBuilder {
-// 	sb.order = "DESC"
-// 	return sb
-// }
-
 // Join sets expressions of JOIN in SELECT.
 //
 // It builds a JOIN expression like
@@ -176,20 +236,214 @@ func (sb *SelectBuilder) JoinWithOption(option JoinOption, table string, onExpr
 	return sb
 }
 
-// BuildWithFlavor returns compiled SELECT string and args with flavor and initial args.
+// ForUpdate adds FOR UPDATE at the end of SELECT statement.
+func (sb *SelectBuilder) ForUpdate() *SelectBuilder {
+	sb.forWhat = "UPDATE"
+	return sb
+}
+
+// ForNoKeyUpdate adds FOR NO KEY UPDATE at the end of SELECT statement.
+func (sb *SelectBuilder) ForNoKeyUpdate() *SelectBuilder {
+	sb.forWhat = "NO KEY UPDATE"
+	return sb
+}
+
+// ForShare adds FOR SHARE at the end of SELECT statement.
+func (sb *SelectBuilder) ForShare() *SelectBuilder {
+	sb.forWhat = "SHARE"
+	return sb
+}
+
+// ForKeyShare adds FOR KEY SHARE at the end of SELECT statement.
+func (sb *SelectBuilder) ForKeyShare() *SelectBuilder {
+	sb.forWhat = "KEY SHARE"
+	return sb
+}
+
+// Of sets tables locked by FOR UPDATE, FOR SHARE, etc.
+func (sb *SelectBuilder) Of(table ...string) *SelectBuilder {
+	sb.forOf = table
+	return sb
+}
+
+// NoWait adds NOWAIT to the locking clause.
+func (sb *SelectBuilder) NoWait() *SelectBuilder {
+	sb.forWait = "NOWAIT"
+	return sb
+}
+
+// SkipLocked adds SKIP LOCKED to the locking clause.
+func (sb *SelectBuilder) SkipLocked() *SelectBuilder {
+	sb.forWait = "SKIP LOCKED"
+	return sb
+}
+
+// InArrayThreshold sets the number of values above which `In` and `NotIn` of the builder
+// send all values as one array parameter. See `Args#SetInArrayThreshold` for details.
+func (sb *SelectBuilder) InArrayThreshold(threshold int) *SelectBuilder {
+	sb.args.SetInArrayThreshold(threshold)
+	return sb
+}
+
+// QuoteIdents quotes every table and column name set in SELECT, DISTINCT ON,
+// FROM, JOIN, GROUP BY, ORDER BY and FOR ... OF, e.g. "demo.user" is built as `"demo"."user"`.
+// Names like "*" or names already quoted by `Ident` are kept as they are.
+//
+// It's useful when names come from user input, like sort columns in query params.
+// As every name is quoted as a whole, expressions and aliases like "count(*)"
+// or "demo.user AS u" cannot be used in this mode.
+// Expressions in WHERE, HAVING and ON are not quoted.
+func (sb *SelectBuilder) QuoteIdents() *SelectBuilder {
+	sb.quoteIdents = true
+	return sb
+}
+
+// Clone returns a deep copy of sb.
+// The copy can be modified and built independently,
+// e.g. a shared base query can be cloned to build a count query and a page query.
+func (sb *SelectBuilder) Clone() *SelectBuilder {
+	clone := *sb
+	clone.args = sb.args.Clone()
+	clone.Cond.Args = clone.args
+	clone.forOf = cloneStrings(sb.forOf)
+	clone.havingExprs = cloneStrings(sb.havingExprs)
+	clone.joinTables = cloneStrings(sb.joinTables)
+	clone.whereExprs = cloneStrings(sb.whereExprs)
+	clone.groupByCols = cloneStrings(sb.groupByCols)
+	clone.orderBy = sb.orderBy.clone()
+	clone.selectCols = cloneStrings(sb.selectCols)
+	clone.tables = cloneStrings(sb.tables)
+	clone.distinctOnCols = cloneStrings(sb.distinctOnCols)
+	clone.windowDefs = cloneStrings(sb.windowDefs)
+
+	if sb.joinOptions != nil {
+		clone.joinOptions = append(make([]JoinOption, 0, len(sb.joinOptions)), sb.joinOptions...)
+	}
+
+	if sb.joinExprs != nil {
+		clone.joinExprs = make([][]string, 0, len(sb.joinExprs))
+
+		for _, exprs := range sb.joinExprs {
+			clone.joinExprs = append(clone.joinExprs, cloneStrings(exprs))
+		}
+	}
+
+	return &clone
+}
+
+func (sb *SelectBuilder) cloneBuilder() Builder {
+	return sb.Clone()
+}
+
+// CountBuilder returns a new builder counting rows selected by sb.
+// ORDER BY, LIMIT, OFFSET and FOR are dropped, while WITH, FROM, JOIN and WHERE are kept with their args.
+// The sb is not changed.
+//
+// If the select list contains plain columns only and there is no DISTINCT, GROUP BY, HAVING or WINDOW,
+// the select list is replaced like
+//
+//	SELECT count(*) FROM t WHERE ...
+//
+// Otherwise, the query is wrapped in a subquery like
+//
+//	SELECT count(*) FROM (SELECT DISTINCT ... FROM t WHERE ...) AS t
+func (sb *SelectBuilder) CountBuilder() *SelectBuilder {
+	cb := sb.Clone()
+	cb.orderBy = orderByClause{}
+	cb.limit = -1
+	cb.offset = -1
+	cb.forWhat = ""
+	cb.forWait = ""
+	cb.forOf = nil
+
+	if cb.countable() {
+		cb.selectCols = []string{"count(*)"}
+		return cb
+	}
+
+	wrapper := newSelectBuilder()
+	return wrapper.Select("count(*)").From(wrapper.BuilderAs(cb, "t"))
+}
+
+// countable reports whether count(*) can replace the select list without changing the result.
+func (sb *SelectBuilder) countable() bool {
+	if sb.distinct || len(sb.groupByCols) > 0 || len(sb.havingExprs) > 0 || len(sb.windowDefs) > 0 {
+		return false
+	}
+
+	// Aggregate, window or set-returning functions change the number of rows.
+	for _, col := range sb.selectCols {
+		if strings.ContainsRune(col, '(') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Build returns compiled SELECT string and args.
 // They can be used in `DB#Query` of package `database/sql` directly.
 func (sb *SelectBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
+	return sb.args.Compile(sb.format(), initialArg...)
+}
+
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (sb *SelectBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = sb.args.CompileE(sb.format(), initialArg...)
+	return sql, args, errors.Join(sb.Validate(), err)
+}
+
+// Validate reports a SELECT without columns,
+// a DISTINCT ON not matching the leftmost ORDER BY expressions,
+// and clauses which cannot be built, e.g. OF or NOWAIT without FOR UPDATE,
+// or FOR UPDATE with DISTINCT, GROUP BY or HAVING.
+func (sb *SelectBuilder) Validate() error {
+	var errs []error
+
+	if len(sb.selectCols) == 0 {
+		errs = append(errs, fmt.Errorf("%w in SELECT", ErrMissingColumns))
+	}
+
+	if !sb.orderByMatchesDistinctOn() {
+		errs = append(errs, fmt.Errorf("%w: DISTINCT ON (%v) ORDER BY %v", ErrDistinctOnOrderBy, strings.Join(sb.distinctOnCols, ", "), sb.orderBy.String()))
+	}
+
+	if sb.forWhat == "" {
+		if len(sb.forOf) > 0 || sb.forWait != "" {
+			errs = append(errs, fmt.Errorf("%w in SELECT: OF, NOWAIT and SKIP LOCKED require a locking clause like FOR UPDATE", ErrInvalidClause))
+		}
+	} else if sb.distinct || len(sb.groupByCols) > 0 || len(sb.havingExprs) > 0 || len(sb.windowDefs) > 0 {
+		errs = append(errs, fmt.Errorf("%w in SELECT: FOR %v is not allowed with DISTINCT, GROUP BY, HAVING or WINDOW", ErrInvalidClause, sb.forWhat))
+	}
+
+	return errors.Join(errs...)
+}
+
+// format returns SELECT in the format of `Args#Compile`.
+func (sb *SelectBuilder) format() string {
 	buf := &strings.Builder{}
+
+	if sb.cteVar != "" {
+		buf.WriteString(sb.cteVar)
+		buf.WriteRune(' ')
+	}
+
 	buf.WriteString("SELECT ")
 
-	if sb.distinct {
+	if len(sb.distinctOnCols) > 0 {
+		buf.WriteString("DISTINCT ON (")
+		buf.WriteString(strings.Join(quoteIdentNames(sb.quoteIdents, sb.distinctOnCols), ", "))
+		buf.WriteString(") ")
+	} else if sb.distinct {
 		buf.WriteString("DISTINCT ")
 	}
 
-	buf.WriteString(strings.Join(sb.selectCols, ", "))
+	buf.WriteString(strings.Join(quoteIdentNames(sb.quoteIdents, sb.selectCols), ", "))
 
-	buf.WriteString(" FROM ")
-	buf.WriteString(strings.Join(sb.tables, ", "))
+	if len(sb.tables) > 0 {
+		buf.WriteString(" FROM ")
+		buf.WriteString(strings.Join(quoteIdentNames(sb.quoteIdents, sb.tables), ", "))
+	}
 
 	for i := range sb.joinTables {
 		if option := sb.joinOptions[i]; option != "" {
@@ -198,7 +452,8 @@ func (sb *SelectBuilder) Build(initialArg ...interface{}) (sql string, args []in
 		}
 
 		buf.WriteString(" JOIN ")
-		buf.WriteString(sb.joinTables[i])
+
+		buf.WriteString(quoteIdentName(sb.quoteIdents, sb.joinTables[i]))
 
 		if exprs := sb.joinExprs[i]; len(exprs) > 0 {
 			buf.WriteString(" ON ")
@@ -215,26 +470,22 @@ func (sb *SelectBuilder) Build(initialArg ...interface{}) (sql string, args []in
 
 	if len(sb.groupByCols) > 0 {
 		buf.WriteString(" GROUP BY ")
-		buf.WriteString(strings.Join(sb.groupByCols, ", "))
-
-		if len(sb.havingExprs) > 0 {
-			buf.WriteString(" HAVING ")
-			buf.WriteString(strings.Join(sb.havingExprs, " AND "))
-		}
-
+		buf.WriteString(strings.Join(quoteIdentNames(sb.quoteIdents, sb.groupByCols), ", "))
 	}
 
-	if len(sb.orderByCols) > 0 {
-		buf.WriteString(" ORDER BY ")
-		buf.WriteString(strings.Join(sb.orderByCols, ", "))
-
-		if sb.order != "" {
-			buf.WriteRune(' ')
-			buf.WriteString(sb.order)
-		}
+	if len(sb.havingExprs) > 0 {
+		buf.WriteString(" HAVING ")
+		buf.WriteString(strings.Join(sb.havingExprs, " AND "))
+	}
 
+	if len(sb.windowDefs) > 0 {
+		buf.WriteString(" WINDOW ")
+		buf.WriteString(strings.Join(sb.windowDefs, ", "))
 	}
 
+	orderBy := sb.orderBy.quoted(sb.quoteIdents)
+	orderBy.writeTo(buf)
+
 	if sb.limit >= 0 {
 		buf.WriteString(" LIMIT ")
 		buf.WriteString(strconv.Itoa(sb.limit))
@@ -249,7 +500,71 @@ func (sb *SelectBuilder) Build(initialArg ...interface{}) (sql string, args []in
 		buf.WriteString(" FOR ")
 		buf.WriteString(sb.forWhat)
 
+		if len(sb.forOf) > 0 {
+			buf.WriteString(" OF ")
+			buf.WriteString(strings.Join(quoteIdentNames(sb.quoteIdents, sb.forOf), ", "))
+		}
+
+		if sb.forWait != "" {
+			buf.WriteRune(' ')
+			buf.WriteString(sb.forWait)
+		}
+	}
+
+	return buf.String()
+}
+
+// orderByMatchesDistinctOn reports whether the leftmost ORDER BY expressions
+// are the DISTINCT ON expressions in any order.
+// Every DISTINCT ON expression must be matched by its own ORDER BY expression,
+// so that repeated ORDER BY expressions cannot stand for other DISTINCT ON expressions.
+func (sb *SelectBuilder) orderByMatchesDistinctOn() bool {
+	if len(sb.distinctOnCols) == 0 || sb.orderBy.empty() {
+		return true
+	}
+
+	if len(sb.orderBy.cols) < len(sb.distinctOnCols) {
+		return false
+	}
+
+	exprs := make(map[string]int, len(sb.distinctOnCols))
+
+	for _, col := range sb.distinctOnCols {
+		exprs[orderByExpr(col)]++
+	}
+
+	for _, col := range sb.orderBy.cols[:len(sb.distinctOnCols)] {
+		expr := orderByExpr(col.expr)
+
+		if exprs[expr] == 0 {
+			return false
+		}
+
+		exprs[expr]--
+	}
+
+	return true
+}
+
+// orderByExpr returns the expression of an ORDER BY item without ASC, DESC or NULLS options.
+func orderByExpr(col string) string {
+	fields := strings.Fields(col)
+
+	for len(fields) > 1 {
+		last := strings.ToUpper(fields[len(fields)-1])
+
+		if last == "ASC" || last == "DESC" {
+			fields = fields[:len(fields)-1]
+			continue
+		}
+
+		if (last == "FIRST" || last == "LAST") && len(fields) > 2 && strings.EqualFold(fields[len(fields)-2], "NULLS") {
+			fields = fields[:len(fields)-2]
+			continue
+		}
+
+		break
 	}
 
-	return sb.args.Compile(buf.String(), initialArg...)
+	return strings.Join(fields, " ")
 }
@@ -122,3 +122,201 @@ func TestSelect9(t *testing.T) {
 	assert.Equal(t, "SELECT * FROM demo.user WHERE (test = $1 AND deleted IS NOT NULL)", result)
 	assert.Equal(t, []interface{}{1}, args)
 }
+
+func TestSelectForUpdate(t *testing.T) {
+	sb := NewSelectBuilder()
+
+	result, args := sb.Select("id", "payload").
+		From("demo.job").
+		Where(sb.EQ("status", "queued")).
+		OrderByAsc("id").
+		Limit(1).
+		ForUpdate().
+		SkipLocked().
+		Build()
+
+	assert.Equal(t, "SELECT id, payload FROM demo.job WHERE status = $1 ORDER BY id ASC LIMIT 1 FOR UPDATE SKIP LOCKED", result)
+	assert.Equal(t, []interface{}{"queued"}, args)
+}
+
+func TestSelectForShare(t *testing.T) {
+	result, _ := Select("*").
+		From("demo.user u").
+		Join("demo.user_profile p", "u.id = p.user_id").
+		ForKeyShare().
+		Of("u", "p").
+		NoWait().
+		Build()
+
+	assert.Equal(t, "SELECT * FROM demo.user u JOIN demo.user_profile p ON u.id = p.user_id FOR KEY SHARE OF u, p NOWAIT", result)
+
+	result, _ = Select("*").From("demo.user").ForNoKeyUpdate().Build()
+	assert.Equal(t, "SELECT * FROM demo.user FOR NO KEY UPDATE", result)
+
+	result, _ = Select("*").From("demo.user").ForShare().Build()
+	assert.Equal(t, "SELECT * FROM demo.user FOR SHARE", result)
+}
+
+func TestSelectDistinct(t *testing.T) {
+	result, _ := Select("name").From("demo.user").Distinct().Build()
+	assert.Equal(t, "SELECT DISTINCT name FROM demo.user", result)
+}
+
+func TestSelectDistinctOn(t *testing.T) {
+	sb := NewSelectBuilder()
+
+	result, args, err := sb.Select("*").
+		From("demo.event").
+		DistinctOn("user_id", "kind").
+		Where(sb.GT("created_at", "2023-01-01")).
+		OrderBy("", "user_id", "kind").
+		OrderByDesc("created_at").
+		BuildE()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (user_id, kind) * FROM demo.event WHERE created_at > $1 ORDER BY user_id, kind, created_at DESC", result)
+	assert.Equal(t, []interface{}{"2023-01-01"}, args)
+
+	result, _, err = Select("*").
+		From("demo.event").
+		DistinctOn("user_id", "kind").
+		OrderBy("", "kind", "user_id DESC", "created_at DESC").
+		BuildE()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (user_id, kind) * FROM demo.event ORDER BY kind, user_id DESC, created_at DESC", result)
+
+	result, _, err = Select("*").
+		From("demo.event").
+		DistinctOn("user_id").
+		BuildE()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (user_id) * FROM demo.event", result)
+}
+
+func TestSelectDistinctOnOrderBy(t *testing.T) {
+	sb := Select("*").From("demo.event").DistinctOn("user_id").OrderByDesc("created_at")
+	result, _, err := sb.BuildE()
+	assert.ErrorIs(t, err, ErrDistinctOnOrderBy)
+	assert.Equal(t, "SELECT DISTINCT ON (user_id) * FROM demo.event ORDER BY created_at DESC", result)
+
+	err = Select("*").From("demo.event").DistinctOn("a", "b").OrderBy("", "a", "a").Validate()
+	assert.ErrorIs(t, err, ErrDistinctOnOrderBy)
+
+	err = Select("*").From("demo.event").DistinctOn("a", "b").OrderByAsc("a").Validate()
+	assert.ErrorIs(t, err, ErrDistinctOnOrderBy)
+
+	err = Select().From("demo.event").DistinctOn("a").OrderByAsc("b").Validate()
+	assert.ErrorIs(t, err, ErrDistinctOnOrderBy)
+	assert.ErrorIs(t, err, ErrMissingColumns)
+}
+
+func TestSelectOrderBy(t *testing.T) {
+	result, _ := Select("*").
+		From("demo.user").
+		OrderByAsc("a").
+		OrderByDesc("b").NullsLast().
+		OrderBy("", "c", "d").Desc().NullsFirst().
+		OrderByUsing(">", "e").
+		Build()
+
+	assert.Equal(t, "SELECT * FROM demo.user ORDER BY a ASC, b DESC NULLS LAST, c DESC NULLS FIRST, d DESC NULLS FIRST, e USING >", result)
+}
+
+func TestSelectQuoteIdents(t *testing.T) {
+	sortCol := `name"; DROP TABLE demo.user; --`
+	sb := NewSelectBuilder()
+	sb.Select("id", "u.name", Ident("u", "email"), "*").
+		From("demo.user").
+		Join("demo.profile", "profile.user_id = user.id").
+		Where(sb.EQ("status", 1)).
+		GroupBy("id").
+		OrderByDesc(sortCol).
+		ForUpdate().Of("user").
+		QuoteIdents()
+
+	result, args := sb.Build()
+
+	assert.Equal(t, `SELECT "id", "u"."name", "u"."email", * FROM "demo"."user" JOIN "demo"."profile" ON profile.user_id = user.id WHERE status = $1 GROUP BY "id" ORDER BY "name""; DROP TABLE demo"."user; --" DESC FOR UPDATE OF "user"`, result)
+	assert.Equal(t, []interface{}{1}, args)
+
+	result, _ = Select("*").From("t").DistinctOn("kind").OrderByAsc("kind").OrderByDesc("created_at").QuoteIdents().Build()
+	assert.Equal(t, `SELECT DISTINCT ON ("kind") * FROM "t" ORDER BY "kind" ASC, "created_at" DESC`, result)
+}
+
+func TestSelectClone(t *testing.T) {
+	base := NewSelectBuilder()
+	base.From("demo.user").Where(base.EQ("status", 1), base.In("id", Select("user_id").From("demo.order").Where("total > 100")))
+
+	count := base.Clone()
+	count.Select("count(*)")
+
+	page := base.Clone()
+	page.Select("id", "name").Where(page.GT("id", 10)).OrderByAsc("id").Limit(20)
+
+	result, args := count.Build()
+	assert.Equal(t, "SELECT count(*) FROM demo.user WHERE status = $1 AND id IN (SELECT user_id FROM demo.order WHERE total > 100)", result)
+	assert.Equal(t, []interface{}{1}, args)
+
+	result, args = page.Build()
+	assert.Equal(t, "SELECT id, name FROM demo.user WHERE status = $1 AND id IN (SELECT user_id FROM demo.order WHERE total > 100) AND id > $2 ORDER BY id ASC LIMIT 20", result)
+	assert.Equal(t, []interface{}{1, 10}, args)
+
+	base.Select("*").Where(base.EQ("name", "a")).OrderByDesc("name")
+	result, args = base.Build()
+	assert.Equal(t, "SELECT * FROM demo.user WHERE status = $1 AND id IN (SELECT user_id FROM demo.order WHERE total > 100) AND name = $2 ORDER BY name DESC", result)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+
+	result, _ = count.Build()
+	assert.Equal(t, "SELECT count(*) FROM demo.user WHERE status = $1 AND id IN (SELECT user_id FROM demo.order WHERE total > 100)", result)
+}
+
+func TestSelectCountBuilder(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("u.id", "u.name").
+		From("demo.user u").
+		Join("demo.profile p", "p.user_id = u.id").
+		Where(sb.EQ("u.status", 1)).
+		OrderByDesc("u.id").
+		Limit(20).
+		Offset(40).
+		ForShare()
+
+	result, args := sb.CountBuilder().Build()
+	assert.Equal(t, "SELECT count(*) FROM demo.user u JOIN demo.profile p ON p.user_id = u.id WHERE u.status = $1", result)
+	assert.Equal(t, []interface{}{1}, args)
+
+	result, args = sb.Build()
+	assert.Equal(t, "SELECT u.id, u.name FROM demo.user u JOIN demo.profile p ON p.user_id = u.id WHERE u.status = $1 ORDER BY u.id DESC LIMIT 20 OFFSET 40 FOR SHARE", result)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sb = NewSelectBuilder()
+	sb.Select("status", "count(*)").
+		From("demo.user").
+		Where(sb.GT("age", 18)).
+		GroupBy("status").
+		Having(sb.GT("count(*)", 10)).
+		OrderByAsc("status").
+		Limit(10)
+
+	result, args = sb.CountBuilder().Build()
+	assert.Equal(t, "SELECT count(*) FROM (SELECT status, count(*) FROM demo.user WHERE age > $1 GROUP BY status HAVING count(*) > $2) AS t", result)
+	assert.Equal(t, []interface{}{18, 10}, args)
+
+	result, _ = Select("email").From("demo.user").Distinct().CountBuilder().Build()
+	assert.Equal(t, "SELECT count(*) FROM (SELECT DISTINCT email FROM demo.user) AS t", result)
+
+	result, _ = Select("max(id)").From("demo.user").CountBuilder().Build()
+	assert.Equal(t, "SELECT count(*) FROM (SELECT max(id) FROM demo.user) AS t", result)
+}
+
+func TestSelectHavingWithoutGroupBy(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("count(*)").From("demo.user").Having(sb.GT("count(*)", 1))
+
+	result, args, err := sb.BuildE()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM demo.user HAVING count(*) > $1", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
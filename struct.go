@@ -0,0 +1,432 @@
+package pgsql
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FieldTag is the struct tag name used by Struct to map fields to columns.
+const FieldTag = "db"
+
+// Field options in FieldTag.
+const (
+	// FieldOptOmitEmpty omits the column in INSERT and UPDATE if the field is a zero value.
+	FieldOptOmitEmpty = "omitempty"
+
+	// FieldOptReadOnly excludes the column from INSERT and UPDATE, e.g. a serial or generated column.
+	FieldOptReadOnly = "readonly"
+
+	// FieldOptPK marks the column as a primary key column.
+	// It's excluded from SET and used in WHERE in UPDATE.
+	FieldOptPK = "pk"
+)
+
+// Struct represents a struct type and maps its fields to columns.
+//
+// Fields are mapped by the tag `db:"col,opt1,opt2"`.
+// If col is empty, the field name is used as column name.
+// Fields tagged with `db:"-"` and unexported fields are ignored.
+// Fields of embedded structs are mapped as if they were fields of the outer struct.
+// If more than one field is mapped to the same column, the shallowest field wins
+// like Go's rule of embedded fields, and the column is ignored if there is more than one shallowest field.
+//
+// Supported options are `omitempty`, `readonly` and `pk`.
+// See FieldOptOmitEmpty, FieldOptReadOnly and FieldOptPK for details.
+type Struct struct {
+	structType reflect.Type
+	fields     []*structField
+}
+
+type structField struct {
+	col       string
+	index     []int
+	omitEmpty bool
+	readOnly  bool
+	pk        bool
+}
+
+// NewStruct analyzes type information in structValue
+// and creates a new Struct with all fields in structValue.
+//
+// If structValue is not a struct or a pointer to struct,
+// the Struct maps no column.
+func NewStruct(structValue interface{}) *Struct {
+	t := reflect.TypeOf(structValue)
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := &Struct{}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return s
+	}
+
+	s.structType = t
+	s.parse(t)
+	return s
+}
+
+// structCache caches Struct by struct type for structOf.
+var structCache sync.Map
+
+// structOf returns a cached Struct for the type of structValue.
+func structOf(structValue interface{}) *Struct {
+	t := reflect.TypeOf(structValue)
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return &Struct{}
+	}
+
+	if s, ok := structCache.Load(t); ok {
+		return s.(*Struct)
+	}
+
+	s, _ := structCache.LoadOrStore(t, NewStruct(structValue))
+	return s.(*Struct)
+}
+
+// parse maps fields of t to columns.
+// Fields at the smallest depth take precedence over fields of embedded structs mapped to the same column.
+func (s *Struct) parse(t reflect.Type) {
+	var fields []*structField
+	collectFields(t, nil, map[reflect.Type]bool{}, &fields)
+
+	depths := make(map[string]int, len(fields))
+	counts := make(map[string]int, len(fields))
+
+	for _, f := range fields {
+		depth, ok := depths[f.col]
+
+		if !ok || len(f.index) < depth {
+			depths[f.col] = len(f.index)
+			counts[f.col] = 1
+		} else if len(f.index) == depth {
+			counts[f.col]++
+		}
+	}
+
+	for _, f := range fields {
+		if len(f.index) == depths[f.col] && counts[f.col] == 1 {
+			s.fields = append(s.fields, f)
+		}
+	}
+}
+
+// collectFields collects all fields of t and its embedded structs in declaration order.
+// Types in visiting are skipped to stop recursion in embedded struct pointers.
+func collectFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, fields *[]*structField) {
+	if visiting[t] {
+		return
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(FieldTag)
+
+		if tag == "-" {
+			continue
+		}
+
+		col, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if f.Anonymous && col == "" {
+			ft := f.Type
+
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, fieldIndex, visiting, fields)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if col == "" {
+			col = f.Name
+		}
+
+		sf := &structField{
+			col:   col,
+			index: fieldIndex,
+		}
+
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case FieldOptOmitEmpty:
+				sf.omitEmpty = true
+			case FieldOptReadOnly:
+				sf.readOnly = true
+			case FieldOptPK:
+				sf.pk = true
+			}
+		}
+
+		*fields = append(*fields, sf)
+	}
+}
+
+// Columns returns all column names mapped by the struct.
+func (s *Struct) Columns() []string {
+	cols := make([]string, 0, len(s.fields))
+
+	for _, f := range s.fields {
+		cols = append(cols, f.col)
+	}
+
+	return cols
+}
+
+// SelectFrom creates a new `SelectBuilder` selecting all columns from table.
+func (s *Struct) SelectFrom(table string) *SelectBuilder {
+	return Select(s.Columns()...).From(table)
+}
+
+// InsertInto creates a new `InsertBuilder` inserting values into table.
+//
+// Read-only columns are skipped.
+// A column with `omitempty` is skipped if its fields are zero values in all values.
+// Values which are not of the struct type or pointers to it are ignored.
+func (s *Struct) InsertInto(table string, value ...interface{}) *InsertBuilder {
+	ib := InsertInto(table)
+	rows := make([]reflect.Value, 0, len(value))
+
+	for _, v := range value {
+		if rv, ok := s.structValue(v); ok {
+			rows = append(rows, rv)
+		}
+	}
+
+	fields := make([]*structField, 0, len(s.fields))
+
+	for _, f := range s.fields {
+		if f.readOnly {
+			continue
+		}
+
+		if f.omitEmpty && !s.hasNonEmpty(rows, f) {
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	cols := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		cols = append(cols, f.col)
+	}
+
+	ib.Cols(cols...)
+
+	for _, rv := range rows {
+		values := make([]interface{}, 0, len(fields))
+
+		for _, f := range fields {
+			values = append(values, fieldValue(rv, f.index))
+		}
+
+		ib.Values(values...)
+	}
+
+	return ib
+}
+
+// Update creates a new `UpdateBuilder` updating table with value.
+//
+// Read-only and primary key columns are not assigned.
+// A column with `omitempty` is not assigned if its field is a zero value.
+// Primary key columns are used in WHERE to update the row of value.
+//
+// If no column is assigned, e.g. all fields are primary keys, read-only or empty,
+// the builder has an empty SET and its `Validate` and `BuildE` report ErrEmptySet.
+// The builder requires WHERE by `UpdateBuilder#RequireWhere`,
+// so `Validate` and `BuildE` report ErrMissingWhere if the struct has no primary key column.
+func (s *Struct) Update(table string, value interface{}) *UpdateBuilder {
+	ub := Update(table).RequireWhere()
+	rv, ok := s.structValue(value)
+
+	if !ok {
+		return ub
+	}
+
+	for _, f := range s.fields {
+		v := fieldValue(rv, f.index)
+
+		if f.pk {
+			ub.Where(ub.EQ(f.col, v))
+			continue
+		}
+
+		if f.readOnly || (f.omitEmpty && isEmptyField(rv, f.index)) {
+			continue
+		}
+
+		ub.SetMore(ub.Assign(f.col, v))
+	}
+
+	return ub
+}
+
+// Values returns values of all mapped fields in value in the order of `Columns`.
+func (s *Struct) Values(value interface{}) []interface{} {
+	rv, ok := s.structValue(value)
+
+	if !ok {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(s.fields))
+
+	for _, f := range s.fields {
+		values = append(values, fieldValue(rv, f.index))
+	}
+
+	return values
+}
+
+// Addr takes address of all mapped fields in value in the order of `Columns`.
+// The value must be a pointer to struct.
+// The result can be used in `Rows#Scan` of package `database/sql` directly.
+func (s *Struct) Addr(value interface{}) []interface{} {
+	return s.AddrWithCols(s.Columns(), value)
+}
+
+// AddrWithCols takes address of fields mapped to cols in value.
+// If a column is not mapped, a placeholder accepting any value is used instead.
+// The value must be a pointer to struct.
+func (s *Struct) AddrWithCols(cols []string, value interface{}) []interface{} {
+	rv := reflect.ValueOf(value)
+
+	if s.structType == nil || rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != s.structType {
+		return nil
+	}
+
+	rv = rv.Elem()
+	addrs := make([]interface{}, 0, len(cols))
+
+	for _, col := range cols {
+		f := s.field(col)
+
+		if f == nil {
+			addrs = append(addrs, new(interface{}))
+			continue
+		}
+
+		addrs = append(addrs, fieldAddr(rv, f.index))
+	}
+
+	return addrs
+}
+
+func (s *Struct) field(col string) *structField {
+	for _, f := range s.fields {
+		if f.col == col {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func (s *Struct) structValue(value interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(value)
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	if s.structType == nil || !rv.IsValid() || rv.Type() != s.structType {
+		return rv, false
+	}
+
+	return rv, true
+}
+
+func (s *Struct) hasNonEmpty(rows []reflect.Value, f *structField) bool {
+	for _, rv := range rows {
+		if !isEmptyField(rv, f.index) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldValue returns the value of field at index.
+// If the field is in a nil embedded struct pointer, it returns nil.
+func fieldValue(v reflect.Value, index []int) interface{} {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(idx)
+	}
+
+	return v.Interface()
+}
+
+func isEmptyField(v reflect.Value, index []int) bool {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return true
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(idx)
+	}
+
+	return v.IsZero()
+}
+
+// fieldAddr returns the address of field at index.
+// Nil embedded struct pointers on the way are allocated if possible.
+// Otherwise, a placeholder accepting any value is returned.
+func fieldAddr(v reflect.Value, index []int) interface{} {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return new(interface{})
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(idx)
+	}
+
+	return v.Addr().Interface()
+}
@@ -0,0 +1,157 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structTestBase struct {
+	CreatedAt int64 `db:"created_at,readonly"`
+	UpdatedAt int64 `db:"updated_at,omitempty"`
+}
+
+type structTestUser struct {
+	structTestBase
+
+	ID       int64   `db:"id,pk,readonly"`
+	Name     string  `db:"name"`
+	Nickname *string `db:"nickname,omitempty"`
+	Status   int
+	Secret   string `db:"-"`
+	ignored  int
+}
+
+var userStruct = NewStruct(new(structTestUser))
+
+func TestStructColumns(t *testing.T) {
+	assert.Equal(t, []string{"created_at", "updated_at", "id", "name", "nickname", "Status"}, userStruct.Columns())
+	assert.Empty(t, NewStruct(1).Columns())
+}
+
+func TestStructSelectFrom(t *testing.T) {
+	sb := userStruct.SelectFrom("demo.user")
+	sb.Where(sb.EQ("id", 1))
+
+	result, args := sb.Build()
+	assert.Equal(t, "SELECT created_at, updated_at, id, name, nickname, Status FROM demo.user WHERE id = $1", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestStructInsertInto(t *testing.T) {
+	nick := "huan"
+	u1 := structTestUser{ID: 1, Name: "Huan Du", Status: 1}
+	u2 := &structTestUser{ID: 2, Name: "Charmy Liu", Nickname: &nick, Status: 2}
+
+	result, args := userStruct.InsertInto("demo.user", u1, u2, "ignored").Returning("id").Build()
+	assert.Equal(t, "INSERT INTO demo.user (name, nickname, Status) VALUES ($1, $2, $3), ($4, $5, $6) RETURNING id", result)
+	assert.Equal(t, []interface{}{"Huan Du", (*string)(nil), 1, "Charmy Liu", &nick, 2}, args)
+
+	result, args = userStruct.InsertInto("demo.user", u1).Build()
+	assert.Equal(t, "INSERT INTO demo.user (name, Status) VALUES ($1, $2)", result)
+	assert.Equal(t, []interface{}{"Huan Du", 1}, args)
+}
+
+func TestStructUpdate(t *testing.T) {
+	u := structTestUser{ID: 1, Name: "Huan Du", Status: 1}
+	u.UpdatedAt = 1234567890
+
+	result, args := userStruct.Update("demo.user", &u).Build()
+	assert.Equal(t, "UPDATE demo.user SET updated_at = $1, name = $2, Status = $3 WHERE id = $4", result)
+	assert.Equal(t, []interface{}{int64(1234567890), "Huan Du", 1, int64(1)}, args)
+}
+
+func TestStructAddr(t *testing.T) {
+	u := &structTestUser{}
+	addrs := userStruct.Addr(u)
+
+	assert.Equal(t, []interface{}{&u.CreatedAt, &u.UpdatedAt, &u.ID, &u.Name, &u.Nickname, &u.Status}, addrs)
+	assert.Len(t, userStruct.Values(u), 6)
+
+	addrs = userStruct.AddrWithCols([]string{"name", "unknown", "id"}, u)
+	assert.Len(t, addrs, 3)
+	assert.Equal(t, &u.Name, addrs[0])
+	assert.Equal(t, &u.ID, addrs[2])
+
+	assert.Nil(t, userStruct.Addr(*u))
+}
+
+type structTestProfile struct {
+	*structTestBase
+
+	UserID int64 `db:"user_id,pk"`
+}
+
+func TestStructEmbeddedPointer(t *testing.T) {
+	s := NewStruct(structTestProfile{})
+	p := &structTestProfile{UserID: 3}
+
+	assert.Equal(t, []interface{}{nil, nil, int64(3)}, s.Values(p))
+
+	// Unexported nil embedded pointer cannot be allocated by reflection.
+	addrs := s.Addr(p)
+	assert.Len(t, addrs, 3)
+	assert.Equal(t, &p.UserID, addrs[2])
+
+	p.structTestBase = &structTestBase{}
+	addrs = s.Addr(p)
+	assert.Equal(t, []interface{}{&p.CreatedAt, &p.UpdatedAt, &p.UserID}, addrs)
+
+	// Only the pk and read-only or empty columns are set.
+	result, _, err := s.Update("demo.profile", p).BuildE()
+	assert.ErrorIs(t, err, ErrEmptySet)
+	assert.Equal(t, "UPDATE demo.profile SET  WHERE user_id = $1", result)
+}
+
+type structTestShadow struct {
+	structTestBase
+
+	UpdatedAt string `db:"updated_at"`
+	Name      string `db:"name"`
+}
+
+type structTestNamed struct {
+	Name string `db:"name"`
+}
+
+type structTestAmbiguous struct {
+	structTestShadow
+	structTestNamed
+
+	ID int64 `db:"id"`
+}
+
+type structTestCycle struct {
+	*structTestCycle
+
+	ID int64 `db:"id"`
+}
+
+func TestStructFieldDepth(t *testing.T) {
+	s := NewStruct(structTestShadow{})
+	v := &structTestShadow{UpdatedAt: "outer"}
+	v.structTestBase.UpdatedAt = 1
+
+	assert.Equal(t, []string{"created_at", "updated_at", "name"}, s.Columns())
+	assert.Equal(t, []interface{}{int64(0), "outer", ""}, s.Values(v))
+	assert.Equal(t, []interface{}{&v.CreatedAt, &v.UpdatedAt, &v.Name}, s.Addr(v))
+
+	// "name" is mapped by two fields at the same depth.
+	s = NewStruct(structTestAmbiguous{})
+	assert.Equal(t, []string{"created_at", "updated_at", "id"}, s.Columns())
+
+	s = NewStruct(structTestCycle{})
+	assert.Equal(t, []string{"id"}, s.Columns())
+}
+
+func TestStructUpdateWithoutPK(t *testing.T) {
+	type noPK struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	result, args, err := NewStruct(noPK{}).Update("demo.user", noPK{ID: 1, Name: "x"}).BuildE()
+	assert.ErrorIs(t, err, ErrMissingWhere)
+	assert.Equal(t, "UPDATE demo.user SET id = $1, name = $2", result)
+	assert.Equal(t, []interface{}{int64(1), "x"}, args)
+}
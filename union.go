@@ -1,16 +1,22 @@
 package pgsql
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
 const (
-	unionDistinct = " UNION " // Default union type is DISTINCT.
-	unionAll      = " UNION ALL "
+	unionDistinct     = " UNION " // Default union type is DISTINCT.
+	unionAll          = " UNION ALL "
+	intersectDistinct = " INTERSECT "
+	intersectAll      = " INTERSECT ALL "
+	exceptDistinct    = " EXCEPT "
+	exceptAll         = " EXCEPT ALL "
 )
 
-// NewUnionBuilder creates a new UNION builder.
+// NewUnionBuilder creates a new set operation builder.
 func NewUnionBuilder() *UnionBuilder {
 	return newUnionBuilder()
 }
@@ -24,15 +30,26 @@ func newUnionBuilder() *UnionBuilder {
 	}
 }
 
-// UnionBuilder is a builder to build UNION.
+// UnionBuilder is a builder to build set operations,
+// which are UNION, INTERSECT and EXCEPT.
+//
+// Every builder is surrounded by parens, so a UnionBuilder can be nested in another one.
+// Operators are applied from left to right. For instance,
+//
+//	Except(a, b).Union(c)
+//
+// builds "(a) EXCEPT (b) UNION (c)" and
+//
+//	Union(a, b).Intersect(c)
+//
+// builds "((a) UNION (b)) INTERSECT (c)" as INTERSECT binds tighter than UNION and EXCEPT.
 type UnionBuilder struct {
-	args        *Args
-	opt         string
-	order       string
-	builders    []Builder
-	orderByCols []string
-	limit       int
-	offset      int
+	args    *Args
+	opts    []string
+	vars    []string
+	orderBy orderByClause
+	limit   int
+	offset  int
 }
 
 // Union unions all builders together using UNION operator.
@@ -40,9 +57,9 @@ func Union(builders ...Builder) *UnionBuilder {
 	return NewUnionBuilder().Union(builders...)
 }
 
-// Union unions all builders together using UNION operator.
+// Union adds builders using UNION operator.
 func (ub *UnionBuilder) Union(builders ...Builder) *UnionBuilder {
-	return ub.union(unionDistinct, builders...)
+	return ub.setOp(unionDistinct, builders...)
 }
 
 // UnionAll unions all builders together using UNION ALL operator.
@@ -50,32 +67,106 @@ func UnionAll(builders ...Builder) *UnionBuilder {
 	return NewUnionBuilder().UnionAll(builders...)
 }
 
-// UnionAll unions all builders together using UNION ALL operator.
+// UnionAll adds builders using UNION ALL operator.
 func (ub *UnionBuilder) UnionAll(builders ...Builder) *UnionBuilder {
-	return ub.union(unionAll, builders...)
+	return ub.setOp(unionAll, builders...)
+}
+
+// Intersect intersects all builders together using INTERSECT operator.
+func Intersect(builders ...Builder) *UnionBuilder {
+	return NewUnionBuilder().Intersect(builders...)
 }
 
-func (ub *UnionBuilder) union(opt string, builders ...Builder) *UnionBuilder {
-	ub.opt = opt
-	ub.builders = builders
+// Intersect adds builders using INTERSECT operator.
+func (ub *UnionBuilder) Intersect(builders ...Builder) *UnionBuilder {
+	return ub.setOp(intersectDistinct, builders...)
+}
+
+// IntersectAll intersects all builders together using INTERSECT ALL operator.
+func IntersectAll(builders ...Builder) *UnionBuilder {
+	return NewUnionBuilder().IntersectAll(builders...)
+}
+
+// IntersectAll adds builders using INTERSECT ALL operator.
+func (ub *UnionBuilder) IntersectAll(builders ...Builder) *UnionBuilder {
+	return ub.setOp(intersectAll, builders...)
+}
+
+// Except subtracts builders from the first one using EXCEPT operator.
+func Except(builders ...Builder) *UnionBuilder {
+	return NewUnionBuilder().Except(builders...)
+}
+
+// Except adds builders using EXCEPT operator.
+func (ub *UnionBuilder) Except(builders ...Builder) *UnionBuilder {
+	return ub.setOp(exceptDistinct, builders...)
+}
+
+// ExceptAll subtracts builders from the first one using EXCEPT ALL operator.
+func ExceptAll(builders ...Builder) *UnionBuilder {
+	return NewUnionBuilder().ExceptAll(builders...)
+}
+
+// ExceptAll adds builders using EXCEPT ALL operator.
+func (ub *UnionBuilder) ExceptAll(builders ...Builder) *UnionBuilder {
+	return ub.setOp(exceptAll, builders...)
+}
+
+func (ub *UnionBuilder) setOp(opt string, builders ...Builder) *UnionBuilder {
+	for _, b := range builders {
+		ub.opts = append(ub.opts, opt)
+		ub.vars = append(ub.vars, ub.Var(b))
+	}
+
 	return ub
 }
 
-// OrderBy sets columns of ORDER BY in SELECT.
+// OrderBy adds columns of ORDER BY in UNION.
+// Use Asc, Desc, NullsFirst or NullsLast to set sort options of these columns.
 func (ub *UnionBuilder) OrderBy(col ...string) *UnionBuilder {
-	ub.orderByCols = col
+	ub.orderBy.add("", col...)
+	return ub
+}
+
+// OrderByAsc adds columns of ORDER BY ASC in UNION.
+func (ub *UnionBuilder) OrderByAsc(col ...string) *UnionBuilder {
+	ub.orderBy.add("ASC", col...)
+	return ub
+}
+
+// OrderByDesc adds columns of ORDER BY DESC in UNION.
+func (ub *UnionBuilder) OrderByDesc(col ...string) *UnionBuilder {
+	ub.orderBy.add("DESC", col...)
+	return ub
+}
+
+// OrderByUsing adds columns of ORDER BY USING op in UNION.
+func (ub *UnionBuilder) OrderByUsing(op string, col ...string) *UnionBuilder {
+	ub.orderBy.add("USING "+op, col...)
 	return ub
 }
 
-// Asc sets order of ORDER BY to ASC.
+// Asc sets order of columns added by the latest OrderBy* call to ASC.
 func (ub *UnionBuilder) Asc() *UnionBuilder {
-	ub.order = "ASC"
+	ub.orderBy.setOrder("ASC")
 	return ub
 }
 
-// Desc sets order of ORDER BY to DESC.
+// Desc sets order of columns added by the latest OrderBy* call to DESC.
 func (ub *UnionBuilder) Desc() *UnionBuilder {
-	ub.order = "DESC"
+	ub.orderBy.setOrder("DESC")
+	return ub
+}
+
+// NullsFirst sorts NULLs first in columns added by the latest OrderBy* call.
+func (ub *UnionBuilder) NullsFirst() *UnionBuilder {
+	ub.orderBy.setNulls("FIRST")
+	return ub
+}
+
+// NullsLast sorts NULLs last in columns added by the latest OrderBy* call.
+func (ub *UnionBuilder) NullsLast() *UnionBuilder {
+	ub.orderBy.setNulls("LAST")
 	return ub
 }
 
@@ -91,41 +182,69 @@ func (ub *UnionBuilder) Offset(offset int) *UnionBuilder {
 	return ub
 }
 
+// Clone returns a deep copy of ub.
+// The copy can be modified and built independently.
+func (ub *UnionBuilder) Clone() *UnionBuilder {
+	clone := *ub
+	clone.args = ub.args.Clone()
+	clone.opts = cloneStrings(ub.opts)
+	clone.vars = cloneStrings(ub.vars)
+	clone.orderBy = ub.orderBy.clone()
+	return &clone
+}
+
+func (ub *UnionBuilder) cloneBuilder() Builder {
+	return ub.Clone()
+}
+
 // Build returns compiled SELECT string and args.
 // They can be used in `DB#Query` of package `database/sql` directly.
 func (ub *UnionBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
-	buf := &strings.Builder{}
-
-	if len(ub.builders) > 0 {
-
-		buf.WriteRune('(')
+	return ub.args.Compile(ub.format(), initialArg...)
+}
 
-		buf.WriteString(ub.Var(ub.builders[0]))
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (ub *UnionBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = ub.args.CompileE(ub.format(), initialArg...)
+	return sql, args, errors.Join(ub.Validate(), err)
+}
 
-		buf.WriteRune(')')
+// Validate reports a set operation without any query.
+func (ub *UnionBuilder) Validate() error {
+	if len(ub.vars) == 0 {
+		return fmt.Errorf("%w in set operation", ErrMissingQuery)
+	}
 
-		for _, b := range ub.builders[1:] {
-			buf.WriteString(ub.opt)
+	return nil
+}
 
-			buf.WriteRune('(')
+// format returns set operation in the format of `Args#Compile`.
+func (ub *UnionBuilder) format() string {
+	buf := &strings.Builder{}
 
-			buf.WriteString(ub.Var(b))
+	if len(ub.vars) > 0 {
+		query := "(" + ub.vars[0] + ")"
+		loose := false // Whether query has operators binding looser than INTERSECT.
 
-			buf.WriteRune(')')
-		}
-	}
+		for i := 1; i < len(ub.vars); i++ {
+			opt := ub.opts[i]
+			intersect := opt == intersectDistinct || opt == intersectAll
 
-	if len(ub.orderByCols) > 0 {
-		buf.WriteString(" ORDER BY ")
-		buf.WriteString(strings.Join(ub.orderByCols, ", "))
+			if intersect && loose {
+				query = "(" + query + ")"
+				loose = false
+			} else if !intersect {
+				loose = true
+			}
 
-		if ub.order != "" {
-			buf.WriteRune(' ')
-			buf.WriteString(ub.order)
+			query += opt + "(" + ub.vars[i] + ")"
 		}
 
+		buf.WriteString(query)
 	}
 
+	ub.orderBy.writeTo(buf)
+
 	if ub.limit >= 0 {
 		buf.WriteString(" LIMIT ")
 		buf.WriteString(strconv.Itoa(ub.limit))
@@ -137,7 +256,7 @@ func (ub *UnionBuilder) Build(initialArg ...interface{}) (sql string, args []int
 		buf.WriteString(strconv.Itoa(ub.offset))
 	}
 
-	return ub.args.Compile(buf.String(), initialArg...)
+	return buf.String()
 }
 
 // Var returns a placeholder for value.
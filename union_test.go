@@ -29,3 +29,58 @@ func TestUnion1(t *testing.T) {
 	assert.Equal(t, "(SELECT id, name, created_at FROM demo.user WHERE id > $1) UNION (SELECT id, avatar FROM demo.user_profile WHERE status IN ($2, $3, $4)) ORDER BY created_at DESC", result)
 	assert.Equal(t, []interface{}{1234, 1, 2, 5}, args)
 }
+
+func TestUnionOrderBy(t *testing.T) {
+	ub := UnionAll(Select("id", "name").From("demo.user"), Select("id", "name").From("demo.admin"))
+	ub.OrderBy("name").Asc().NullsLast().OrderByDesc("id").Limit(10)
+
+	result, _ := ub.Build()
+
+	assert.Equal(t, "(SELECT id, name FROM demo.user) UNION ALL (SELECT id, name FROM demo.admin) ORDER BY name ASC NULLS LAST, id DESC LIMIT 10", result)
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewSelectBuilder()
+	a.Select("id").From("demo.ledger").Where(a.EQ("source", "bank"))
+
+	b := NewSelectBuilder()
+	b.Select("id").From("demo.ledger").Where(b.EQ("source", "books"))
+
+	c := NewSelectBuilder()
+	c.Select("id").From("demo.adjustment").Where(c.GT("amount", 0))
+
+	result, args := Except(a, b).Union(c).Build()
+	assert.Equal(t, "(SELECT id FROM demo.ledger WHERE source = $1) EXCEPT (SELECT id FROM demo.ledger WHERE source = $2) UNION (SELECT id FROM demo.adjustment WHERE amount > $3)", result)
+	assert.Equal(t, []interface{}{"bank", "books", 0}, args)
+
+	result, _ = UnionAll(a, b).IntersectAll(c).Build()
+	assert.Equal(t, "((SELECT id FROM demo.ledger WHERE source = $1) UNION ALL (SELECT id FROM demo.ledger WHERE source = $2)) INTERSECT ALL (SELECT id FROM demo.adjustment WHERE amount > $3)", result)
+
+	result, _ = Intersect(a, b).ExceptAll(c).Build()
+	assert.Equal(t, "(SELECT id FROM demo.ledger WHERE source = $1) INTERSECT (SELECT id FROM demo.ledger WHERE source = $2) EXCEPT ALL (SELECT id FROM demo.adjustment WHERE amount > $3)", result)
+}
+
+func TestSetOperationsNested(t *testing.T) {
+	a := Select("id").From("demo.a")
+	b := Select("id").From("demo.b")
+	c := NewSelectBuilder()
+	c.Select("id").From("demo.c").Where(c.EQ("kind", 1))
+
+	ub := Union(Except(a, b), c)
+	ub.OrderByDesc("id").Limit(5)
+
+	result, args := ub.Build()
+	assert.Equal(t, "((SELECT id FROM demo.a) EXCEPT (SELECT id FROM demo.b)) UNION (SELECT id FROM demo.c WHERE kind = $1) ORDER BY id DESC LIMIT 5", result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestUnionClone(t *testing.T) {
+	base := Union(Select("id").From("demo.user"), Select("id").From("demo.admin"))
+	clone := base.Clone().UnionAll(Select("id").From("demo.guest")).OrderByAsc("id")
+
+	result, _ := base.Build()
+	assert.Equal(t, "(SELECT id FROM demo.user) UNION (SELECT id FROM demo.admin)", result)
+
+	result, _ = clone.Build()
+	assert.Equal(t, "(SELECT id FROM demo.user) UNION (SELECT id FROM demo.admin) UNION ALL (SELECT id FROM demo.guest) ORDER BY id ASC", result)
+}
@@ -1,8 +1,8 @@
 package pgsql
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -26,15 +26,20 @@ func newUpdateBuilder() *UpdateBuilder {
 type UpdateBuilder struct {
 	Cond
 
-	args *Args
+	args   *Args
+	cteVar string
 
 	table       string
-	order       string
 	returning   []string
 	assignments []string
+	fromTables  []string
 	whereExprs  []string
-	orderByCols []string
+	orderBy     orderByClause
+	keyCols     []string
 	limit       int
+
+	requireWhere bool
+	quoteIdents  bool
 }
 
 // Update sets table name in UPDATE.
@@ -48,6 +53,12 @@ func (ub *UpdateBuilder) Update(table string) *UpdateBuilder {
 	return ub
 }
 
+// With sets the WITH clause of UPDATE.
+func (ub *UpdateBuilder) With(cte *CTEBuilder) *UpdateBuilder {
+	ub.cteVar = ub.Var(cte)
+	return ub
+}
+
 // Set sets the assignments in SET.
 func (ub *UpdateBuilder) Set(assignment ...string) *UpdateBuilder {
 	ub.assignments = assignment
@@ -60,12 +71,39 @@ func (ub *UpdateBuilder) SetMore(assignment ...string) *UpdateBuilder {
 	return ub
 }
 
+// From sets tables of FROM in UPDATE.
+// They can be referenced in SET and WHERE to update rows by joining other tables.
+func (ub *UpdateBuilder) From(table ...string) *UpdateBuilder {
+	ub.fromTables = table
+	return ub
+}
+
+// BuilderAs returns an AS expression wrapping a complex SQL.
+// According to SQL syntax, SQL built by builder is surrounded by parens.
+func (ub *UpdateBuilder) BuilderAs(builder Builder, alias string) string {
+	return fmt.Sprintf("(%s) AS %s", ub.Var(builder), alias)
+}
+
+// RequireWhere makes `BuildE` and `Validate` report ErrMissingWhere
+// if there is no WHERE expression, so that all rows are never updated by mistake.
+func (ub *UpdateBuilder) RequireWhere() *UpdateBuilder {
+	ub.requireWhere = true
+	return ub
+}
+
 // Where sets expressions of WHERE in UPDATE.
 func (ub *UpdateBuilder) Where(andExpr ...string) *UpdateBuilder {
 	ub.whereExprs = append(ub.whereExprs, andExpr...)
 	return ub
 }
 
+// Returning sets columns or expressions of RETURNING in UPDATE.
+// Use "*" to return all columns.
+func (ub *UpdateBuilder) Returning(col ...string) *UpdateBuilder {
+	ub.returning = col
+	return ub
+}
+
 // Assign represents SET "field = value" in UPDATE.
 func (ub *UpdateBuilder) Assign(field string, value interface{}) string {
 	return fmt.Sprintf("%s = %s", field, ub.args.Add(value))
@@ -107,21 +145,60 @@ func (ub *UpdateBuilder) Div(field string, value interface{}) string {
 	return fmt.Sprintf("%s = %s / %s", f, f, ub.args.Add(value))
 }
 
-// OrderBy sets columns of ORDER BY in UPDATE.
+// KeyCols sets columns identifying rows when ORDER BY or LIMIT is set.
+// The default key column is "ctid".
+// See doc in `UpdateBuilder#Build` for details.
+func (ub *UpdateBuilder) KeyCols(col ...string) *UpdateBuilder {
+	ub.keyCols = col
+	return ub
+}
+
+// OrderBy adds columns of ORDER BY in UPDATE.
+// Use Asc, Desc, NullsFirst or NullsLast to set sort options of these columns.
 func (ub *UpdateBuilder) OrderBy(col ...string) *UpdateBuilder {
-	ub.orderByCols = col
+	ub.orderBy.add("", col...)
+	return ub
+}
+
+// OrderByAsc adds columns of ORDER BY ASC in UPDATE.
+func (ub *UpdateBuilder) OrderByAsc(col ...string) *UpdateBuilder {
+	ub.orderBy.add("ASC", col...)
+	return ub
+}
+
+// OrderByDesc adds columns of ORDER BY DESC in UPDATE.
+func (ub *UpdateBuilder) OrderByDesc(col ...string) *UpdateBuilder {
+	ub.orderBy.add("DESC", col...)
+	return ub
+}
+
+// OrderByUsing adds columns of ORDER BY USING op in UPDATE.
+func (ub *UpdateBuilder) OrderByUsing(op string, col ...string) *UpdateBuilder {
+	ub.orderBy.add("USING "+op, col...)
 	return ub
 }
 
-// Asc sets order of ORDER BY to ASC.
+// Asc sets order of columns added by the latest OrderBy* call to ASC.
 func (ub *UpdateBuilder) Asc() *UpdateBuilder {
-	ub.order = "ASC"
+	ub.orderBy.setOrder("ASC")
 	return ub
 }
 
-// Desc sets order of ORDER BY to DESC.
+// Desc sets order of columns added by the latest OrderBy* call to DESC.
 func (ub *UpdateBuilder) Desc() *UpdateBuilder {
-	ub.order = "DESC"
+	ub.orderBy.setOrder("DESC")
+	return ub
+}
+
+// NullsFirst sorts NULLs first in columns added by the latest OrderBy* call.
+func (ub *UpdateBuilder) NullsFirst() *UpdateBuilder {
+	ub.orderBy.setNulls("FIRST")
+	return ub
+}
+
+// NullsLast sorts NULLs last in columns added by the latest OrderBy* call.
+func (ub *UpdateBuilder) NullsLast() *UpdateBuilder {
+	ub.orderBy.setNulls("LAST")
 	return ub
 }
 
@@ -131,42 +208,122 @@ func (ub *UpdateBuilder) Limit(limit int) *UpdateBuilder {
 	return ub
 }
 
-// BuildWithFlavor returns compiled UPDATE string and args with flavor and initial args.
+// InArrayThreshold sets the number of values above which `In` and `NotIn` of the builder
+// send all values as one array parameter. See `Args#SetInArrayThreshold` for details.
+func (ub *UpdateBuilder) InArrayThreshold(threshold int) *UpdateBuilder {
+	ub.args.SetInArrayThreshold(threshold)
+	return ub
+}
+
+// QuoteIdents quotes the table and every name set in FROM, RETURNING, ORDER BY and KeyCols,
+// e.g. "demo.user" is built as `"demo"."user"`.
+// Names like "*" or names already quoted by `Ident` are kept as they are.
+// As every name is quoted as a whole, aliases like "demo.user AS u" cannot be used in this mode.
+// Assignments in SET and expressions in WHERE are not quoted.
+func (ub *UpdateBuilder) QuoteIdents() *UpdateBuilder {
+	ub.quoteIdents = true
+	return ub
+}
+
+// Clone returns a deep copy of ub.
+// The copy can be modified and built independently.
+func (ub *UpdateBuilder) Clone() *UpdateBuilder {
+	clone := *ub
+	clone.args = ub.args.Clone()
+	clone.Cond.Args = clone.args
+	clone.returning = cloneStrings(ub.returning)
+	clone.assignments = cloneStrings(ub.assignments)
+	clone.fromTables = cloneStrings(ub.fromTables)
+	clone.whereExprs = cloneStrings(ub.whereExprs)
+	clone.orderBy = ub.orderBy.clone()
+	clone.keyCols = cloneStrings(ub.keyCols)
+	return &clone
+}
+
+func (ub *UpdateBuilder) cloneBuilder() Builder {
+	return ub.Clone()
+}
+
+// Build returns compiled UPDATE string and args.
 // They can be used in `DB#Query` of package `database/sql` directly.
+//
+// PostgreSQL doesn't support ORDER BY and LIMIT in UPDATE.
+// If any of them is set, rows to update are selected by a subquery like
+//
+//	UPDATE t SET ... WHERE ctid IN (SELECT ctid FROM t WHERE ... ORDER BY ... LIMIT n FOR UPDATE)
+//
+// Use `KeyCols` to match rows by other columns, e.g. the primary key.
 func (ub *UpdateBuilder) Build(initialArg ...interface{}) (sql string, args []interface{}) {
-	buf := &strings.Builder{}
-	buf.WriteString("UPDATE ")
-	buf.WriteString(ub.table)
+	return ub.args.Compile(ub.format(), initialArg...)
+}
 
-	buf.WriteString(" SET ")
-	buf.WriteString(strings.Join(ub.assignments, ", "))
+// BuildE is like Build but also reports errors found by Validate and in compiling args.
+func (ub *UpdateBuilder) BuildE(initialArg ...interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = ub.args.CompileE(ub.format(), initialArg...)
+	return sql, args, errors.Join(ub.Validate(), err)
+}
 
-	if len(ub.whereExprs) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(ub.whereExprs, " AND "))
+// Validate reports an UPDATE without table, without assignments,
+// without WHERE if `RequireWhere` is set, or with KeyCols but no ORDER BY or LIMIT.
+func (ub *UpdateBuilder) Validate() error {
+	var errs []error
+
+	if ub.table == "" {
+		errs = append(errs, fmt.Errorf("%w in UPDATE", ErrMissingTable))
+	}
+
+	if len(ub.assignments) == 0 {
+		errs = append(errs, fmt.Errorf("%w in UPDATE", ErrEmptySet))
+	}
+
+	if ub.requireWhere && len(ub.whereExprs) == 0 {
+		errs = append(errs, fmt.Errorf("%w in UPDATE", ErrMissingWhere))
+	}
+
+	if len(ub.keyCols) > 0 && ub.orderBy.empty() && ub.limit < 0 {
+		errs = append(errs, fmt.Errorf("%w in UPDATE: KeyCols requires ORDER BY or LIMIT", ErrInvalidClause))
 	}
 
-	if len(ub.orderByCols) > 0 {
-		buf.WriteString(" ORDER BY ")
-		buf.WriteString(strings.Join(ub.orderByCols, ", "))
+	return errors.Join(errs...)
+}
 
-		if ub.order != "" {
-			buf.WriteRune(' ')
-			buf.WriteString(ub.order)
-		}
+// format returns UPDATE in the format of `Args#Compile`.
+func (ub *UpdateBuilder) format() string {
+	buf := &strings.Builder{}
 
+	if ub.cteVar != "" {
+		buf.WriteString(ub.cteVar)
+		buf.WriteRune(' ')
 	}
 
-	if ub.limit >= 0 {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.Itoa(ub.limit))
+	table := quoteIdentName(ub.quoteIdents, ub.table)
+	fromTables := quoteIdentNames(ub.quoteIdents, ub.fromTables)
+
+	buf.WriteString("UPDATE ")
+	buf.WriteString(table)
+
+	buf.WriteString(" SET ")
+	buf.WriteString(strings.Join(ub.assignments, ", "))
+
+	if len(ub.fromTables) > 0 {
+		buf.WriteString(" FROM ")
+		buf.WriteString(strings.Join(fromTables, ", "))
+	}
 
+	lw := &limitedWhere{
+		table:      table,
+		joinTables: fromTables,
+		whereExprs: ub.whereExprs,
+		keyCols:    quoteIdentNames(ub.quoteIdents, ub.keyCols),
+		orderBy:    ub.orderBy.quoted(ub.quoteIdents),
+		limit:      ub.limit,
 	}
+	lw.writeTo(buf)
 
 	if len(ub.returning) != 0 {
 		buf.WriteString(" RETURNING ")
-		buf.WriteString(strings.Join(ub.returning, ", "))
+		buf.WriteString(strings.Join(quoteIdentNames(ub.quoteIdents, ub.returning), ", "))
 	}
 
-	return ub.args.Compile(buf.String(), initialArg...)
+	return buf.String()
 }
@@ -37,6 +37,110 @@ func TestUpdate2(t *testing.T) {
 
 	result, args := ub.Build()
 
-	assert.Equal(t, "UPDATE demo.user SET type = $1, credit = credit + 1, modified_at = UNIX_TIMESTAMP(NOW()) WHERE id > $2 AND name LIKE $3 AND (id_card IS NULL OR status IN ($4, $5, $6)) AND modified_at > created_at + $7 ORDER BY id ASC", result)
+	assert.Equal(t, "UPDATE demo.user SET type = $1, credit = credit + 1, modified_at = UNIX_TIMESTAMP(NOW()) WHERE ctid IN (SELECT ctid FROM demo.user WHERE id > $2 AND name LIKE $3 AND (id_card IS NULL OR status IN ($4, $5, $6)) AND modified_at > created_at + $7 ORDER BY id ASC FOR UPDATE)", result)
 	assert.Equal(t, []interface{}{"sys", 1234, "%Du", 1, 2, 5, 86400}, args)
 }
+
+func TestUpdateReturning(t *testing.T) {
+	ub := NewUpdateBuilder()
+	ub.Update("demo.order")
+	ub.Set(ub.Assign("status", "shipped"))
+	ub.Where(ub.EQ("status", "paid"), ub.EQ("id", 42))
+	ub.Returning("id", "status", "price * "+ub.Var(1.2)+" AS gross")
+
+	result, args := ub.Build()
+
+	assert.Equal(t, "UPDATE demo.order SET status = $1 WHERE status = $2 AND id = $3 RETURNING id, status, price * $4 AS gross", result)
+	assert.Equal(t, []interface{}{"shipped", "paid", 42, 1.2}, args)
+
+	result, _ = Update("demo.order").Set("status = 'shipped'").Returning("*").Build()
+	assert.Equal(t, "UPDATE demo.order SET status = 'shipped' RETURNING *", result)
+}
+
+func TestUpdateFrom(t *testing.T) {
+	ub := NewUpdateBuilder()
+	ub.Update("demo.order o")
+	ub.Set("status = s.status", ub.Assign("synced_at", "2023-01-01"))
+	ub.From("demo.order_staging s")
+	ub.Where("o.id = s.order_id", ub.EQ("s.batch", 3))
+
+	result, args := ub.Build()
+
+	assert.Equal(t, "UPDATE demo.order o SET status = s.status, synced_at = $1 FROM demo.order_staging s WHERE o.id = s.order_id AND s.batch = $2", result)
+	assert.Equal(t, []interface{}{"2023-01-01", 3}, args)
+}
+
+func TestUpdateFromBuilder(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("user_id", "sum(amount) AS total").
+		From("demo.payment").
+		Where(sb.GT("created_at", "2023-01-01")).
+		GroupBy("user_id")
+
+	ub := NewUpdateBuilder()
+	ub.Update("demo.user u")
+	ub.Set(ub.Assign("tier", "gold"))
+	ub.From(ub.BuilderAs(sb, "p"))
+	ub.Where("u.id = p.user_id", ub.GE("p.total", 1000))
+
+	result, args := ub.Build()
+
+	assert.Equal(t, "UPDATE demo.user u SET tier = $1 FROM (SELECT user_id, sum(amount) AS total FROM demo.payment WHERE created_at > $2 GROUP BY user_id) AS p WHERE u.id = p.user_id AND p.total >= $3", result)
+	assert.Equal(t, []interface{}{"gold", "2023-01-01", 1000}, args)
+}
+
+func TestUpdateKeyColsLimit(t *testing.T) {
+	ub := NewUpdateBuilder()
+	ub.Update("demo.job")
+	ub.Set(ub.Assign("status", "running"))
+	ub.Where(ub.EQ("status", "queued"))
+	ub.OrderBy("priority").Desc()
+	ub.Limit(5)
+	ub.KeyCols("tenant_id", "id")
+	ub.Returning("id")
+
+	result, args := ub.Build()
+
+	assert.Equal(t, "UPDATE demo.job SET status = $1 WHERE (tenant_id, id) IN (SELECT tenant_id, id FROM demo.job WHERE status = $2 ORDER BY priority DESC LIMIT 5 FOR UPDATE) RETURNING id", result)
+	assert.Equal(t, []interface{}{"running", "queued"}, args)
+
+	result, _ = Update(Ident("my table")).Set("a = 1").From("b").Limit(1).Build()
+	assert.Equal(t, `UPDATE "my table" SET a = 1 FROM b WHERE "my table".ctid IN (SELECT "my table".ctid FROM "my table", b LIMIT 1 FOR UPDATE OF "my table")`, result)
+
+	result, _ = Update(Ident("demo", "my table") + ` AS "my t"`).Set("a = 1").From("b").Limit(1).Build()
+	assert.Equal(t, `UPDATE "demo"."my table" AS "my t" SET a = 1 FROM b WHERE "my t".ctid IN (SELECT "my t".ctid FROM "demo"."my table" AS "my t", b LIMIT 1 FOR UPDATE OF "my t")`, result)
+}
+
+func TestUpdateQuoteIdents(t *testing.T) {
+	ub := NewUpdateBuilder()
+	ub.Update("demo.job")
+	ub.Set(ub.Assign("status", "running"))
+	ub.Where(ub.EQ("status", "queued"))
+	ub.OrderBy("priority").Desc()
+	ub.Limit(5)
+	ub.KeyCols("id")
+	ub.Returning("id", "user$")
+	ub.QuoteIdents()
+
+	result, args := ub.Build()
+
+	assert.Equal(t, `UPDATE "demo"."job" SET status = $1 WHERE "id" IN (SELECT "id" FROM "demo"."job" WHERE status = $2 ORDER BY "priority" DESC LIMIT 5 FOR UPDATE) RETURNING "id", "user$"`, result)
+	assert.Equal(t, []interface{}{"running", "queued"}, args)
+}
+
+func TestUpdateClone(t *testing.T) {
+	base := NewUpdateBuilder()
+	base.Update("demo.user").Set(base.Assign("status", 1))
+
+	clone := base.Clone()
+	clone.Where(clone.EQ("id", 2))
+	base.Where(base.EQ("name", "a"))
+
+	result, args := base.Build()
+	assert.Equal(t, "UPDATE demo.user SET status = $1 WHERE name = $2", result)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+
+	result, args = clone.Build()
+	assert.Equal(t, "UPDATE demo.user SET status = $1 WHERE id = $2", result)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
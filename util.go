@@ -2,23 +2,185 @@ package pgsql
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 )
 
-// Escape replaces `$` with `$$` in ident.
-// func Escape(ident string) string {
-// 	return strings.Replace(ident, "$", "$$", -1)
-// }
+// Escape replaces `$` with `$$` in ident,
+// so that ident can be used in any builder as it is.
+func Escape(ident string) string {
+	return strings.Replace(ident, "$", "$$", -1)
+}
 
 // EscapeAll replaces `$` with `$$` in all strings of ident.
-// func EscapeAll(ident ...string) []string {
-// 	escaped := make([]string, 0, len(ident))
+func EscapeAll(ident ...string) []string {
+	escaped := make([]string, 0, len(ident))
+
+	for _, i := range ident {
+		escaped = append(escaped, Escape(i))
+	}
+
+	return escaped
+}
+
+// QuoteIdent quotes name as an identifier, e.g. `QuoteIdent(`my"table`)` returns `"my""table"`.
+// The name is not split by dots. Use `Ident` for a qualified name.
+//
+// Quoted identifiers are case-sensitive in PostgreSQL,
+// so the name must be in the same case as it's created.
+//
+// The `$` in name is escaped as `$$`, so that the result can be used in any builder.
+func QuoteIdent(name string) string {
+	return Escape(quoteIdent(name))
+}
+
+// quoteIdent quotes name as an identifier without escaping `$`.
+func quoteIdent(name string) string {
+	// PostgreSQL identifiers cannot contain NUL.
+	if i := strings.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
 
-// 	for _, i := range ident {
-// 		escaped = append(escaped, Escape(i))
-// 	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// QuoteLiteral quotes s as a string literal with single quotes doubled.
+// If s contains any backslash, s is quoted as an escape string
+// with backslashes doubled, which means the same regardless of standard_conforming_strings.
+//
+//	QuoteLiteral("it's")  // 'it''s'
+//	QuoteLiteral(`a\b`)   // E'a\\b'
+//
+// The `$` in s is escaped as `$$`, so that the result can be used in any builder.
+// Use the result in a builder only, as `$$` means two dollars in raw SQL.
+func QuoteLiteral(s string) string {
+	return Escape(quoteLiteral(s))
+}
+
+// Ident returns a qualified identifier with all parts quoted,
+// e.g. `Ident("demo", "user")` returns `"demo"."user"`.
+// A part "*" is not quoted, e.g. `Ident("u", "*")` returns `"u".*`.
+//
+// The `$` in parts is escaped as `$$`,
+// so that the result can be used as a table or column in any builder.
+func Ident(parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+
+	for _, p := range parts {
+		if p == "*" {
+			quoted = append(quoted, p)
+			continue
+		}
+
+		quoted = append(quoted, quoteIdent(p))
+	}
 
-// 	return escaped
-// }
+	return Escape(strings.Join(quoted, "."))
+}
+
+// quoteIdentName quotes a possibly qualified name split by dots if quote is true.
+//
+// A name created by `Ident` is returned as it is.
+// Any other name, even if it looks quoted, is quoted as a whole again with `$` escaped,
+// so that a name from user input like `"a$1"` cannot refer to args or break out of quotes.
+func quoteIdentName(quote bool, name string) string {
+	if !quote || name == "*" || isIdent(name) {
+		return name
+	}
+
+	return Ident(strings.Split(name, ".")...)
+}
+
+// quoteIdentNames quotes names by quoteIdentName if quote is true.
+func quoteIdentNames(quote bool, names []string) []string {
+	if !quote {
+		return names
+	}
+
+	quoted := make([]string, 0, len(names))
+
+	for _, name := range names {
+		quoted = append(quoted, quoteIdentName(true, name))
+	}
+
+	return quoted
+}
+
+// isIdent reports whether name is exactly what `Ident` returns for some parts.
+func isIdent(name string) bool {
+	parts, ok := unquoteIdent(name)
+	return ok && Ident(parts...) == name
+}
+
+// unquoteIdent splits name created by `Ident` into unquoted parts.
+// It returns false if name is not a list of quoted identifiers split by dots
+// with an optional "*" at the end, or if any `$` in name is not escaped as `$$`.
+func unquoteIdent(name string) (parts []string, ok bool) {
+	if name == "" || name[0] != '"' {
+		return nil, false
+	}
+
+	for len(name) > 0 {
+		if name == "*" {
+			return append(parts, name), true
+		}
+
+		if name[0] != '"' {
+			return nil, false
+		}
+
+		part := &strings.Builder{}
+		i := 1
+
+		for ; i < len(name); i++ {
+			c := name[i]
+
+			if c == '$' {
+				if i+1 >= len(name) || name[i+1] != '$' {
+					return nil, false
+				}
+
+				i++
+			} else if c == '"' {
+				if i+1 >= len(name) || name[i+1] != '"' {
+					break
+				}
+
+				i++
+			}
+
+			part.WriteByte(c)
+		}
+
+		if i >= len(name) || i == 1 {
+			return nil, false
+		}
+
+		parts = append(parts, part.String())
+		name = name[i+1:]
+
+		if name == "" {
+			return parts, true
+		}
+
+		if name[0] != '.' || len(name) == 1 {
+			return nil, false
+		}
+
+		name = name[1:]
+	}
+
+	return parts, true
+}
+
+// cloneStrings returns a copy of s.
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return append(make([]string, 0, len(s)), s...)
+}
 
 // Flatten recursively extracts values in slices and returns
 // a flattened []interface{} with all values.
@@ -68,6 +230,12 @@ func Raw(expr string) interface{} {
 	return rawArgs{expr}
 }
 
+// Default represents the DEFAULT keyword.
+// It can be used as a value in INSERT or assigned to a column in UPDATE.
+func Default() interface{} {
+	return rawArgs{"DEFAULT"}
+}
+
 type listArgs struct {
 	args []interface{}
 }
@@ -78,6 +246,56 @@ func List(arg interface{}) interface{} {
 	return listArgs{Flatten(arg)}
 }
 
+type castArgs struct {
+	arg interface{}
+	typ string
+}
+
+// Cast marks arg to be cast to typ, e.g. `Cast(ids, "bigint[]")` is compiled to `$1::bigint[]`.
+// If arg is a Builder, it's compiled to `(SELECT ...)::typ`.
+func Cast(arg interface{}, typ string) interface{} {
+	return castArgs{
+		arg: arg,
+		typ: typ,
+	}
+}
+
+// arrayOf converts values to a slice typed by the type of values, e.g. `[]int64`.
+// It returns false if values are not of the same type or any value is nil, a Builder or a special arg.
+func arrayOf(values []interface{}) (interface{}, bool) {
+	if len(values) == 0 || values[0] == nil {
+		return nil, false
+	}
+
+	t := reflect.TypeOf(values[0])
+	slice := reflect.MakeSlice(reflect.SliceOf(t), 0, len(values))
+
+	for _, v := range values {
+		switch v.(type) {
+		case Builder, rawArgs, listArgs, namedArgs, castArgs:
+			return nil, false
+		}
+
+		if v == nil || reflect.TypeOf(v) != t {
+			return nil, false
+		}
+
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+
+	return slice.Interface(), true
+}
+
+// useArray reports whether values should be sent as an array parameter according to threshold.
+// See `Args#SetInArrayThreshold` for details.
+func useArray(threshold int, values []interface{}) (interface{}, bool) {
+	if threshold <= 0 || len(values) <= threshold {
+		return nil, false
+	}
+
+	return arrayOf(values)
+}
+
 type namedArgs struct {
 	arg  interface{}
 	name string
@@ -85,10 +303,173 @@ type namedArgs struct {
 
 // Named creates a named argument.
 // Unlike `sql.Named`, this named argument works only with `Build` or `BuildNamed` for convenience
-// and will be replaced to a `?` after `Compile`.
+// and will be replaced to a `$n` after `Compile`.
+// All `${name}` referring the same name are replaced to the same `$n`.
+// Adding a named argument with the same name and an equal value again, e.g. in two `Cond` methods,
+// refers to the first value and shares its `$n` as well.
+// Adding it with a different value is reported by `BuildE` and `Args#CompileE` with ErrDuplicateName.
 func Named(name string, arg interface{}) interface{} {
 	return namedArgs{
 		name: name,
 		arg:  arg,
 	}
 }
+
+// defaultKeyCol is the key column used to match rows selected by ORDER BY and LIMIT
+// in UPDATE and DELETE.
+const defaultKeyCol = "ctid"
+
+// limitedWhere describes the WHERE, ORDER BY and LIMIT of an UPDATE or DELETE.
+type limitedWhere struct {
+	table      string
+	joinTables []string
+	whereExprs []string
+	keyCols    []string
+	orderBy    orderByClause
+	limit      int
+}
+
+// writeTo writes WHERE clause to buf.
+//
+// PostgreSQL doesn't support ORDER BY and LIMIT in UPDATE and DELETE.
+// If any of them is set, rows are matched by key columns selected in a subquery like
+//
+//	WHERE ctid IN (SELECT ctid FROM table WHERE ... ORDER BY ... LIMIT n FOR UPDATE)
+//
+// Rows are locked by FOR UPDATE in the subquery, so that a concurrent update
+// cannot change a row between selecting it and writing it.
+//
+// When there are joined tables, WHERE expressions are kept in the outer query as well
+// to join rows, and key columns are qualified by table name or alias.
+// Both WHERE share the same args and placeholders.
+// Only rows of the table are locked by FOR UPDATE OF the table name or alias.
+func (lw *limitedWhere) writeTo(buf *strings.Builder) {
+	if lw.orderBy.empty() && lw.limit < 0 {
+		if len(lw.whereExprs) > 0 {
+			buf.WriteString(" WHERE ")
+			buf.WriteString(strings.Join(lw.whereExprs, " AND "))
+		}
+
+		return
+	}
+
+	keyCols := lw.keyCols
+
+	if len(keyCols) == 0 {
+		keyCols = []string{defaultKeyCol}
+	}
+
+	if len(lw.joinTables) > 0 {
+		ref := tableRef(lw.table)
+		qualified := make([]string, 0, len(keyCols))
+
+		for _, col := range keyCols {
+			if !strings.ContainsRune(col, '.') {
+				col = ref + "." + col
+			}
+
+			qualified = append(qualified, col)
+		}
+
+		keyCols = qualified
+	}
+
+	keys := strings.Join(keyCols, ", ")
+
+	buf.WriteString(" WHERE ")
+
+	if len(lw.joinTables) > 0 && len(lw.whereExprs) > 0 {
+		buf.WriteString(strings.Join(lw.whereExprs, " AND "))
+		buf.WriteString(" AND ")
+	}
+
+	if len(keyCols) > 1 {
+		buf.WriteString("(")
+		buf.WriteString(keys)
+		buf.WriteString(")")
+	} else {
+		buf.WriteString(keys)
+	}
+
+	buf.WriteString(" IN (SELECT ")
+	buf.WriteString(keys)
+	buf.WriteString(" FROM ")
+	buf.WriteString(lw.table)
+
+	for _, t := range lw.joinTables {
+		buf.WriteString(", ")
+		buf.WriteString(t)
+	}
+
+	if len(lw.whereExprs) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(lw.whereExprs, " AND "))
+	}
+
+	lw.orderBy.writeTo(buf)
+
+	if lw.limit >= 0 {
+		buf.WriteString(" LIMIT ")
+		buf.WriteString(strconv.Itoa(lw.limit))
+	}
+
+	buf.WriteString(" FOR UPDATE")
+
+	if len(lw.joinTables) > 0 {
+		buf.WriteString(" OF ")
+		buf.WriteString(tableRef(lw.table))
+	}
+
+	buf.WriteString(")")
+}
+
+// tableRef returns the name referring table in expressions,
+// which is the alias if any, e.g. "u" for "demo.user AS u".
+// Spaces in quoted identifiers like `"my table"` don't split the name.
+func tableRef(table string) string {
+	ref := ""
+	start := -1
+	quoted := false
+
+	for i := 0; i < len(table); i++ {
+		c := table[i]
+
+		if quoted {
+			if c == '"' {
+				// A doubled quote is an escaped quote.
+				if i+1 < len(table) && table[i+1] == '"' {
+					i++
+				} else {
+					quoted = false
+				}
+			}
+
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			if start >= 0 {
+				ref = table[start:i]
+				start = -1
+			}
+		case '"':
+			quoted = true
+			fallthrough
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+
+	if start >= 0 {
+		ref = table[start:]
+	}
+
+	if ref == "" {
+		return table
+	}
+
+	return ref
+}
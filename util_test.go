@@ -0,0 +1,84 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"user"`, QuoteIdent("user"))
+	assert.Equal(t, `"my""table"`, QuoteIdent(`my"table`))
+	assert.Equal(t, `"a.b"`, QuoteIdent("a.b"))
+	assert.Equal(t, `"ab"`, QuoteIdent("ab\x00cd"))
+
+	assert.Equal(t, `'it''s'`, QuoteLiteral("it's"))
+	assert.Equal(t, `E'a\\b'`, QuoteLiteral(`a\b`))
+
+	assert.Equal(t, `"a$$0"`, QuoteIdent("a$0"))
+	assert.Equal(t, `'$$0'`, QuoteLiteral("$0"))
+
+	sb := Select("*").From(QuoteIdent("a$0"))
+	sb.Where("name = "+QuoteLiteral("$0"), sb.EQ("id", 1))
+
+	result, args := sb.Build()
+	assert.Equal(t, `SELECT * FROM "a$0" WHERE name = '$0' AND id = $1`, result)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestIdent(t *testing.T) {
+	assert.Equal(t, `"demo"."user"`, Ident("demo", "user"))
+	assert.Equal(t, `"u".*`, Ident("u", "*"))
+	assert.Equal(t, `"price$$"`, Ident("price$"))
+
+	result, _ := Select(Ident("u", "id")).From(Ident("demo", "user") + " u").Build()
+	assert.Equal(t, `SELECT "u"."id" FROM "demo"."user" u`, result)
+
+	result, _ = Select(Ident("price$")).From("t").Build()
+	assert.Equal(t, `SELECT "price$" FROM t`, result)
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "a$$b", Escape("a$b"))
+	assert.Equal(t, []string{"$$1", "b"}, EscapeAll("$1", "b"))
+}
+
+func TestUnquoteIdent(t *testing.T) {
+	cases := []struct {
+		name  string
+		parts []string
+	}{
+		{`"a"`, []string{"a"}},
+		{`"a"."b"`, []string{"a", "b"}},
+		{`"a"".b"`, []string{`a".b`}},
+		{`"a".*`, []string{"a", "*"}},
+		{`"a$$1"`, []string{"a$1"}},
+	}
+
+	for _, c := range cases {
+		parts, ok := unquoteIdent(c.name)
+		assert.True(t, ok, c.name)
+		assert.Equal(t, c.parts, parts, c.name)
+		assert.True(t, isIdent(c.name), c.name)
+	}
+
+	for _, name := range []string{`a`, `""`, `"a`, `"a".`, `"a" u`, `"a".b`, `"a$1"`, `"a$"`} {
+		_, ok := unquoteIdent(name)
+		assert.False(t, ok, name)
+		assert.False(t, isIdent(name), name)
+	}
+}
+
+func TestQuoteIdentsHostile(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select("id", `"a$0"`).From("demo.user").Where(sb.In("id", Select("x").From("y"))).OrderByDesc(`"b$1"`).QuoteIdents()
+
+	result, args, err := sb.BuildE()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "id", """a$0""" FROM "demo"."user" WHERE id IN (SELECT x FROM y) ORDER BY """b$1""" DESC`, result)
+	assert.Empty(t, args)
+
+	result, args = Select(Ident("a$0")).From("t").QuoteIdents().Build()
+	assert.Equal(t, `SELECT "a$0" FROM "t"`, result)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,184 @@
+package pgsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Frame bounds of a window frame.
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	CurrentRow         = "CURRENT ROW"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+)
+
+// Preceding represents the frame bound "offset PRECEDING".
+// The offset is an integer for ROWS and GROUPS or a value like "'1 day'" for RANGE.
+func Preceding(offset interface{}) string {
+	return fmt.Sprintf("%v PRECEDING", offset)
+}
+
+// Following represents the frame bound "offset FOLLOWING".
+// The offset is an integer for ROWS and GROUPS or a value like "'1 day'" for RANGE.
+func Following(offset interface{}) string {
+	return fmt.Sprintf("%v FOLLOWING", offset)
+}
+
+// FrameExclusion is the option in EXCLUDE of a window frame.
+type FrameExclusion string
+
+// Frame exclusion options.
+const (
+	ExcludeCurrentRow FrameExclusion = "CURRENT ROW"
+	ExcludeGroup      FrameExclusion = "GROUP"
+	ExcludeTies       FrameExclusion = "TIES"
+	ExcludeNoOthers   FrameExclusion = "NO OTHERS"
+)
+
+// NewWindow creates a new window specification.
+func NewWindow() *Window {
+	return &Window{}
+}
+
+// Window is a window specification used in OVER and WINDOW clauses.
+//
+// It builds a window specification like
+//
+//	base PARTITION BY col... ORDER BY col... frame EXCLUDE exclusion
+type Window struct {
+	base        string
+	frame       string
+	exclusion   FrameExclusion
+	partitionBy []string
+	orderBy     orderByClause
+}
+
+// Base sets the name of an existing window which this window is based on.
+func (w *Window) Base(name string) *Window {
+	w.base = name
+	return w
+}
+
+// PartitionBy sets columns of PARTITION BY in window.
+func (w *Window) PartitionBy(col ...string) *Window {
+	w.partitionBy = append(w.partitionBy, col...)
+	return w
+}
+
+// OrderBy adds columns of ORDER BY in window.
+// Use Asc, Desc, NullsFirst or NullsLast to set sort options of these columns.
+func (w *Window) OrderBy(col ...string) *Window {
+	w.orderBy.add("", col...)
+	return w
+}
+
+// OrderByAsc adds columns of ORDER BY ASC in window.
+func (w *Window) OrderByAsc(col ...string) *Window {
+	w.orderBy.add("ASC", col...)
+	return w
+}
+
+// OrderByDesc adds columns of ORDER BY DESC in window.
+func (w *Window) OrderByDesc(col ...string) *Window {
+	w.orderBy.add("DESC", col...)
+	return w
+}
+
+// OrderByUsing adds columns of ORDER BY USING op in window.
+func (w *Window) OrderByUsing(op string, col ...string) *Window {
+	w.orderBy.add("USING "+op, col...)
+	return w
+}
+
+// Asc sets order of columns added by the latest OrderBy* call to ASC.
+func (w *Window) Asc() *Window {
+	w.orderBy.setOrder("ASC")
+	return w
+}
+
+// Desc sets order of columns added by the latest OrderBy* call to DESC.
+func (w *Window) Desc() *Window {
+	w.orderBy.setOrder("DESC")
+	return w
+}
+
+// NullsFirst sorts NULLs first in columns added by the latest OrderBy* call.
+func (w *Window) NullsFirst() *Window {
+	w.orderBy.setNulls("FIRST")
+	return w
+}
+
+// NullsLast sorts NULLs last in columns added by the latest OrderBy* call.
+func (w *Window) NullsLast() *Window {
+	w.orderBy.setNulls("LAST")
+	return w
+}
+
+// Rows sets the frame in ROWS mode.
+// If end is empty, the frame is "ROWS start".
+// Otherwise, it's "ROWS BETWEEN start AND end".
+func (w *Window) Rows(start, end string) *Window {
+	return w.setFrame("ROWS", start, end)
+}
+
+// Range sets the frame in RANGE mode.
+// If end is empty, the frame is "RANGE start".
+// Otherwise, it's "RANGE BETWEEN start AND end".
+func (w *Window) Range(start, end string) *Window {
+	return w.setFrame("RANGE", start, end)
+}
+
+// Groups sets the frame in GROUPS mode.
+// If end is empty, the frame is "GROUPS start".
+// Otherwise, it's "GROUPS BETWEEN start AND end".
+func (w *Window) Groups(start, end string) *Window {
+	return w.setFrame("GROUPS", start, end)
+}
+
+func (w *Window) setFrame(mode, start, end string) *Window {
+	if end == "" {
+		w.frame = mode + " " + start
+	} else {
+		w.frame = mode + " BETWEEN " + start + " AND " + end
+	}
+
+	return w
+}
+
+// Exclude sets the EXCLUDE option of the frame.
+func (w *Window) Exclude(exclusion FrameExclusion) *Window {
+	w.exclusion = exclusion
+	return w
+}
+
+// Over represents "fn OVER (window)".
+func (w *Window) Over(fn string) string {
+	return fmt.Sprintf("%s OVER (%s)", fn, w.String())
+}
+
+// String returns the window specification without surrounding parens.
+func (w *Window) String() string {
+	parts := make([]string, 0, 5)
+
+	if w.base != "" {
+		parts = append(parts, w.base)
+	}
+
+	if len(w.partitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(w.partitionBy, ", "))
+	}
+
+	if !w.orderBy.empty() {
+		parts = append(parts, "ORDER BY "+w.orderBy.String())
+	}
+
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+
+		if w.exclusion != "" {
+			parts = append(parts, "EXCLUDE "+string(w.exclusion))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
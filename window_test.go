@@ -0,0 +1,47 @@
+package pgsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow1(t *testing.T) {
+	w := NewWindow().PartitionBy("user_id").OrderBy("created_at DESC")
+	assert.Equal(t, "row_number() OVER (PARTITION BY user_id ORDER BY created_at DESC)", w.Over("row_number()"))
+
+	w = NewWindow().PartitionBy("account_id").OrderBy("day").Rows(UnboundedPreceding, CurrentRow)
+	assert.Equal(t, "PARTITION BY account_id ORDER BY day ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW", w.String())
+
+	w = NewWindow().Base("w").Range(Preceding("'7 days'"), Following("'1 day'")).Exclude(ExcludeTies)
+	assert.Equal(t, "avg(amount) OVER (w RANGE BETWEEN '7 days' PRECEDING AND '1 day' FOLLOWING EXCLUDE TIES)", w.Over("avg(amount)"))
+
+	w = NewWindow().OrderBy("score").Groups(Preceding(1), "").Exclude(ExcludeCurrentRow)
+	assert.Equal(t, "ORDER BY score GROUPS 1 PRECEDING EXCLUDE CURRENT ROW", w.String())
+}
+
+func TestSelectWindow(t *testing.T) {
+	sb := NewSelectBuilder()
+	sb.Select(
+		"account_id",
+		"rank() OVER w",
+		"sum(amount) OVER (w ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+	).
+		From("demo.payment").
+		Where(sb.GT("amount", 0)).
+		GroupBy("account_id", "amount").
+		Having(sb.GT("count(*)", 1)).
+		Window("w", NewWindow().PartitionBy("account_id").OrderBy("amount DESC")).
+		OrderByAsc("account_id").
+		Limit(10)
+
+	result, args := sb.Build()
+
+	assert.Equal(t, "SELECT account_id, rank() OVER w, sum(amount) OVER (w ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM demo.payment WHERE amount > $1 GROUP BY account_id, amount HAVING count(*) > $2 WINDOW w AS (PARTITION BY account_id ORDER BY amount DESC) ORDER BY account_id ASC LIMIT 10", result)
+	assert.Equal(t, []interface{}{0, 1}, args)
+}
+
+func TestWindowOrderBy(t *testing.T) {
+	w := NewWindow().PartitionBy("user_id").OrderByDesc("score").NullsLast().OrderByAsc("id")
+	assert.Equal(t, "PARTITION BY user_id ORDER BY score DESC NULLS LAST, id ASC", w.String())
+}